@@ -7,7 +7,7 @@ resource "onepassword_item_credit_card" "this" {
     type        = "visa"
     number      = "4111 1111 1111 1111"
     cvv         = "1111"
-    expiry_date = 202205
-    valid_from  = 201805
+    expiry_date = "2022-05"
+    valid_from  = "2018-05"
   }
 }
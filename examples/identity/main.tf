@@ -7,7 +7,7 @@ resource "onepassword_item_identity" "this" {
     initial    = "AN#24"
     lastname   = "Nasinnyk"
     sex        = "male"
-    birth_date = 575553660         //unix-time
+    birth_date = "1988-03-29"
     occupation = "Play Basketball"
     company    = "HarshPhil"
     department = "Guards"
@@ -51,7 +51,7 @@ resource "onepassword_item_common" "this" {
 
     field {
       name       = "member since"
-      month_year = 201903
+      month_year = "2019-03"
     }
   }
 }
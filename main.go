@@ -1,15 +1,67 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
 	"github.com/anasinnyk/terraform-provider-1password/onepassword"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := generate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: func() *schema.Provider {
 			return onepassword.Provider()
 		},
 	})
 }
+
+// generate writes Terraform configuration for the items of a vault,
+// the provider is configured from the same environment variables as in Terraform
+func generate(args []string) (err error) {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	vault := flags.String("vault", "", "name or UUID of the vault to generate configuration for")
+	out := flags.String("out", "", "file to write the configuration to, defaults to stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *vault == "" {
+		return errors.New("-vault is required")
+	}
+
+	ctx := context.Background()
+	p := onepassword.Provider()
+	if diags := p.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{})); diags.HasError() {
+		return fmt.Errorf("could not configure provider: %s", diags[0].Summary)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		// close reports failed writes on some file systems, the configuration is incomplete then
+		defer func() {
+			if cerr := f.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}()
+		w = f
+	}
+	return onepassword.GenerateConfig(ctx, w, p.Meta(), *vault)
+}
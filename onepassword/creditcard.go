@@ -0,0 +1,146 @@
+package onepassword
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cardTypes are the card_type values 1Password knows
+var cardTypes = []string{
+	"mc",
+	"visa",
+	"amex",
+	"diners",
+	"carteblanche",
+	"discover",
+	"jcb",
+	"maestro",
+	"visaelectron",
+	"laser",
+	"unionpay",
+}
+
+// cardIINRange maps the issuer identification number prefixes from min to max to a card type
+type cardIINRange struct {
+	min, max int
+	cardType string
+}
+
+// cardIINRanges are checked in order, so narrow ranges go before the wider ones they overlap with
+var cardIINRanges = []cardIINRange{
+	{4026, 4026, "visaelectron"},
+	{417500, 417500, "visaelectron"},
+	{4508, 4508, "visaelectron"},
+	{4844, 4844, "visaelectron"},
+	{4913, 4913, "visaelectron"},
+	{4917, 4917, "visaelectron"},
+	{4, 4, "visa"},
+	{34, 34, "amex"},
+	{37, 37, "amex"},
+	{300, 305, "diners"},
+	{36, 36, "diners"},
+	{38, 39, "diners"},
+	{3528, 3589, "jcb"},
+	{51, 55, "mc"},
+	{2221, 2720, "mc"},
+	{6011, 6011, "discover"},
+	{622126, 622925, "discover"},
+	{644, 649, "discover"},
+	{65, 65, "discover"},
+	{6304, 6304, "laser"},
+	{6706, 6706, "laser"},
+	{6709, 6709, "laser"},
+	{6771, 6771, "laser"},
+	{62, 62, "unionpay"},
+	{5018, 5018, "maestro"},
+	{5020, 5020, "maestro"},
+	{5038, 5038, "maestro"},
+	{5893, 5893, "maestro"},
+	{6759, 6759, "maestro"},
+	{6761, 6763, "maestro"},
+	{50, 50, "maestro"},
+	{56, 58, "maestro"},
+}
+
+// cardNumberDigits strips the spaces and dashes card numbers are usually grouped with
+func cardNumberDigits(number string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(number)
+}
+
+// luhnValid checks the digits against the Luhn checksum all payment card numbers carry
+func luhnValid(digits string) bool {
+	if len(digits) < 12 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	for i := range digits {
+		d := int(digits[len(digits)-1-i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// detectCardType returns the card type of the issuer identification number,
+// an empty string when the number isn't in a known range
+func detectCardType(digits string) string {
+	for _, r := range cardIINRanges {
+		n := len(strconv.Itoa(r.min))
+		if len(digits) < n {
+			continue
+		}
+		prefix, err := strconv.Atoi(digits[:n])
+		if err != nil {
+			return ""
+		}
+		if prefix >= r.min && prefix <= r.max {
+			return r.cardType
+		}
+	}
+	return ""
+}
+
+// cardLast4 returns the last four digits of the card number
+func cardLast4(number string) string {
+	digits := cardNumberDigits(number)
+	if len(digits) < 4 {
+		return digits
+	}
+	return digits[len(digits)-4:]
+}
+
+// cardDetails reads the number and the YYYYMM expiry date from the main section of the card item
+func cardDetails(sections []Section) (number string, expiry int) {
+	for _, section := range sections {
+		if section.Name != "" {
+			continue
+		}
+		for _, field := range section.Fields {
+			switch field.N {
+			case "ccnum":
+				number, _ = field.Value.(string)
+			case "expiry":
+				expiry = dateTimeValue(field.Value)
+			}
+		}
+	}
+	return number, expiry
+}
+
+// cardExpired reports whether the YYYYMM expiry date has passed, cards are valid until the end of the month
+func cardExpired(expiry int, now time.Time) bool {
+	if expiry == 0 {
+		return false
+	}
+	end := time.Date(expiry/100, time.Month(expiry%100)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !now.Before(end)
+}
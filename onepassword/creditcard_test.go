@@ -0,0 +1,189 @@
+package onepassword
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestCardNumber(t *testing.T) {
+	tests := []struct {
+		number   string
+		valid    bool
+		cardType string
+	}{
+		{number: "4111 1111 1111 1111", valid: true, cardType: "visa"},
+		{number: "4111 1111 1111 1112", valid: false, cardType: "visa"},
+		{number: "4917-6100-0000-0000", valid: true, cardType: "visaelectron"},
+		{number: "5555555555554444", valid: true, cardType: "mc"},
+		{number: "2223003122003222", valid: true, cardType: "mc"},
+		{number: "378282246310005", valid: true, cardType: "amex"},
+		{number: "30569309025904", valid: true, cardType: "diners"},
+		{number: "6011111111111117", valid: true, cardType: "discover"},
+		{number: "3530111333300000", valid: true, cardType: "jcb"},
+		{number: "6759649826438453", valid: true, cardType: "maestro"},
+		{number: "6200000000000005", valid: true, cardType: "unionpay"},
+		{number: "9111111111111111", valid: false, cardType: ""},
+		{number: "4111", valid: false, cardType: "visa"},
+		{number: "4111 1111 1111 111x", valid: false, cardType: "visa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			digits := cardNumberDigits(tt.number)
+			if got := luhnValid(digits); got != tt.valid {
+				t.Errorf("luhnValid() = %v, want %v", got, tt.valid)
+			}
+			if got := detectCardType(digits); got != tt.cardType {
+				t.Errorf("detectCardType() = %q, want %q", got, tt.cardType)
+			}
+		})
+	}
+}
+
+func TestCardExpired(t *testing.T) {
+	now := time.Date(2022, 5, 31, 23, 59, 0, 0, time.UTC)
+	tests := []struct {
+		expiry int
+		want   bool
+	}{
+		{expiry: 0, want: false},
+		{expiry: 202204, want: true},
+		{expiry: 202205, want: false},
+		{expiry: 202212, want: false},
+	}
+	for _, tt := range tests {
+		if got := cardExpired(tt.expiry, now); got != tt.want {
+			t.Errorf("cardExpired(%d) = %v, want %v", tt.expiry, got, tt.want)
+		}
+	}
+	if !cardExpired(202212, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("cardExpired(202212) = false on 2023-01-01, want true")
+	}
+}
+
+func TestResourceItemCreditCardFlatten_derived(t *testing.T) {
+	tests := []struct {
+		name        string
+		sections    []Section
+		wantLast4   string
+		wantExpired bool
+	}{
+		{
+			name: "main section",
+			sections: []Section{{Name: "", Fields: []SectionField{
+				{N: "ccnum", Type: TypeString, Value: "5555 5555 5555 4444"},
+				{N: "expiry", Type: TypeMonthYear, Value: float64(202205)},
+			}}},
+			wantLast4:   "4444",
+			wantExpired: true,
+		},
+		{name: "no main section"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceItemCreditCard()
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"main": []interface{}{map[string]interface{}{"number": "4111111111111111", "expiry_date": "2000-01"}},
+			})
+			v := &Item{UUID: "card", Template: Category2Template(CreditCardCategory), Details: Details{Sections: tt.sections}}
+			if diags := resourceItemCreditCardFlatten(context.Background(), d, nil, v); diags.HasError() {
+				t.Fatal(diags)
+			}
+			if got := d.Get("last4"); got != tt.wantLast4 {
+				t.Errorf("last4 = %v, want %v", got, tt.wantLast4)
+			}
+			if got := d.Get("expired"); got != tt.wantExpired {
+				t.Errorf("expired = %v, want %v", got, tt.wantExpired)
+			}
+		})
+	}
+}
+
+func TestResourceItemCreditCard_derived(t *testing.T) {
+	f := newFakeOP(t)
+	vault := f.AddVault("cards")
+	meta := &Meta{
+		data:          schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{}),
+		onePassClient: f.client(),
+	}
+	res := exampleResource{
+		Type: "onepassword_item_credit_card",
+		Name: "this",
+		Config: map[string]interface{}{
+			"name":  "Default",
+			"vault": vault.UUID,
+			"main": []interface{}{map[string]interface{}{
+				"number":      "5555 5555 5555 4444",
+				"expiry_date": "2022-05",
+			}},
+		},
+	}
+	testRoundTrip(t, meta, res)
+
+	r := resourceItemCreditCard()
+	d := schema.TestResourceDataRaw(t, r.Schema, res.Config)
+	if diags := r.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if got := d.Get("main.0.type"); got != "mc" {
+		t.Errorf("main.0.type = %v, want mc", got)
+	}
+	if got := d.Get("last4"); got != "4444" {
+		t.Errorf("last4 = %v, want 4444", got)
+	}
+	if got := d.Get("expired"); got != true {
+		t.Errorf("expired = %v, want true", got)
+	}
+
+	if diags := r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"main": []interface{}{map[string]interface{}{"number": "5555 5555 5555 4445"}},
+	})); !diags.HasError() {
+		t.Error("Validate() accepted a number with an invalid check digit")
+	}
+}
+
+func TestResourceItemCreditCard_typeDiff(t *testing.T) {
+	state := &terraform.InstanceState{ID: "card", Attributes: map[string]string{
+		"id":            "card",
+		"main.#":        "1",
+		"main.0.type":   "visa",
+		"main.0.number": "4111111111111111",
+	}}
+	tests := []struct {
+		name         string
+		main         map[string]interface{}
+		wantComputed bool
+		wantType     string
+	}{
+		{
+			name:         "number changed",
+			main:         map[string]interface{}{"number": "5555555555554444"},
+			wantComputed: true,
+		},
+		{
+			name:     "number changed, type configured",
+			main:     map[string]interface{}{"number": "5555555555554444", "type": "mc"},
+			wantType: "mc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := resourceItemCreditCard().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+				"main": []interface{}{tt.main},
+			}), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			attr, ok := diff.Attributes["main.0.type"]
+			if !ok {
+				t.Fatal("Diff() keeps the detected type of the old number")
+			}
+			if attr.NewComputed != tt.wantComputed || attr.New != tt.wantType {
+				t.Errorf("Diff() main.0.type = %q (computed %v), want %q (computed %v)", attr.New, attr.NewComputed, tt.wantType, tt.wantComputed)
+			}
+		})
+	}
+}
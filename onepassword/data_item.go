@@ -0,0 +1,202 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceItemSchema adds the flattened field maps to the item schema
+// and drops the attributes which only control how the resource is destroyed
+func dataSourceItemSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	delete(s, "archive_on_destroy")
+	delete(s, "deletion_protection")
+	s["fields"] = &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Sensitive:   true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "Values of all item fields keyed by \"section.field\"",
+	}
+	s["field_types"] = &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "Types of all item fields keyed by \"section.field\", e.g. string, concealed or url",
+	}
+	s["tag"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Look the item up by one of its tags",
+	}
+	if _, ok := s["url"]; !ok {
+		s["url"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Look the item up by one of its urls",
+		}
+	}
+	s["field_match"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Look the item up by values of its fields, all of them have to match",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"section": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"field": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"value": {
+					Type:      schema.TypeString,
+					Required:  true,
+					Sensitive: true,
+				},
+			},
+		},
+	}
+	return s
+}
+
+// dataSourceItemRead looks the item up by tag, url or field values when they are set,
+// reads it once and sets the resource attributes with flatten and the flattened field maps
+func dataSourceItemRead(flatten itemFlattenFunc, categories ...Category) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		m := meta.(*Meta)
+		var v *Item
+		if d.Id() == "" && hasItemLookup(d) {
+			var err error
+			if v, err = lookupItem(ctx, m.onePassClient, d, categories); err != nil {
+				return diag.FromErr(err)
+			}
+		} else {
+			var err error
+			if v, err = m.onePassClient.ReadItem(ctx, getID(d), d.Get("vault").(string)); err != nil {
+				return diag.FromErr(err)
+			}
+			if v == nil {
+				return diag.Errorf("item %s not found", getID(d))
+			}
+		}
+		if diags := flatten(ctx, d, meta, v); diags.HasError() {
+			return diags
+		}
+		values, types := ProcessFieldMap(v.Details)
+		if err := d.Set("fields", values); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("field_types", types); err != nil {
+			return diag.FromErr(err)
+		}
+		return nil
+	}
+}
+
+func hasItemLookup(d *schema.ResourceData) bool {
+	return d.Get("tag").(string) != "" || d.Get("url").(string) != "" || len(d.Get("field_match").([]interface{})) > 0
+}
+
+// lookupItem returns the only item of the categories matching the name, tag, url
+// and field values set in the data source. The listed items are narrowed down by
+// name, tag and url first, as list items doesn't return field values every item
+// left is read when field values have to match.
+func lookupItem(ctx context.Context, o *OnePassClient, d *schema.ResourceData, categories []Category) (*Item, error) {
+	vaultID := d.Get("vault").(string)
+	name := d.Get("name").(string)
+	tag := d.Get("tag").(string)
+	url := d.Get("url").(string)
+	matches := d.Get("field_match").([]interface{})
+
+	items, err := o.ListItems(ctx, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []Item
+	for _, item := range items {
+		if checkItemCategory(&item, categories...) != nil {
+			continue
+		}
+		if name != "" && item.Overview.Title != name {
+			continue
+		}
+		if tag != "" && !stringInSlice(tag, item.Overview.Tags) {
+			continue
+		}
+		if url != "" && !itemHasURL(item.Overview, url) {
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+
+	var found []*Item
+	for i := range candidates {
+		if len(matches) == 0 {
+			found = append(found, &candidates[i])
+			continue
+		}
+		v, err := o.ReadItem(ctx, candidates[i].UUID, vaultID)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil && itemFieldsMatch(v.Details, matches) {
+			found = append(found, v)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, errors.New("no item matches the lookup arguments")
+	case 1:
+		if len(matches) > 0 {
+			return found[0], nil
+		}
+		// listed items have no details
+		v, err := o.ReadItem(ctx, found[0].UUID, vaultID)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			return nil, fmt.Errorf("item %s not found", found[0].UUID)
+		}
+		return v, nil
+	}
+	ids := make([]string, 0, len(found))
+	for _, v := range found {
+		ids = append(ids, v.UUID)
+	}
+	return nil, fmt.Errorf("%d items match the lookup arguments, narrow them down or use one of the UUIDs: %s", len(found), strings.Join(ids, ", "))
+}
+
+func itemHasURL(o Overview, url string) bool {
+	if o.URL == url {
+		return true
+	}
+	for _, u := range o.URLs {
+		if u.Href == url {
+			return true
+		}
+	}
+	return false
+}
+
+func itemFieldsMatch(details Details, matches []interface{}) bool {
+	values, _ := ProcessFieldMap(details)
+	for _, m := range matches {
+		match := m.(map[string]interface{})
+		key := match["field"].(string)
+		if section := match["section"].(string); section != "" {
+			key = section + "." + key
+		}
+		if v, ok := values[key]; !ok || v != match["value"].(string) {
+			return false
+		}
+	}
+	return true
+}
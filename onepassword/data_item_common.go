@@ -8,7 +8,7 @@ func dataSourceItemCommon() *schema.Resource {
 	s["template"].Optional = true
 
 	return &schema.Resource{
-		ReadContext: resourceItemCommonRead,
-		Schema:      s,
+		ReadContext: dataSourceItemRead(resourceItemCommonFlatten, commonCategories...),
+		Schema:      dataSourceItemSchema(s),
 	}
 }
@@ -4,7 +4,7 @@ import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 func dataSourceItemCreditCard() *schema.Resource {
 	return &schema.Resource{
-		ReadContext: resourceItemCreditCardRead,
-		Schema:      resourceItemCreditCard().Schema,
+		ReadContext: dataSourceItemRead(resourceItemCreditCardFlatten, CreditCardCategory),
+		Schema:      dataSourceItemSchema(resourceItemCreditCard().Schema),
 	}
 }
@@ -4,7 +4,7 @@ import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 func dataSourceItemPassword() *schema.Resource {
 	return &schema.Resource{
-		ReadContext: resourceItemPasswordRead,
-		Schema:      resourceItemPassword().Schema,
+		ReadContext: dataSourceItemRead(resourceItemPasswordFlatten, PasswordCategory),
+		Schema:      dataSourceItemSchema(resourceItemPassword().Schema),
 	}
 }
@@ -4,7 +4,7 @@ import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 func dataSourceItemSecureNote() *schema.Resource {
 	return &schema.Resource{
-		ReadContext: resourceItemSecureNoteRead,
-		Schema:      resourceItemSecureNote().Schema,
+		ReadContext: dataSourceItemRead(resourceItemSecureNoteFlatten, SecureNoteCategory),
+		Schema:      dataSourceItemSchema(resourceItemSecureNote().Schema),
 	}
 }
@@ -4,7 +4,7 @@ import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 func dataSourceItemSoftwareLicense() *schema.Resource {
 	return &schema.Resource{
-		ReadContext: resourceItemSoftwareLicenseRead,
-		Schema:      resourceItemSoftwareLicense().Schema,
+		ReadContext: dataSourceItemRead(resourceItemSoftwareLicenseFlatten, SoftwareLicenseCategory),
+		Schema:      dataSourceItemSchema(resourceItemSoftwareLicense().Schema),
 	}
 }
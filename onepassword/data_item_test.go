@@ -0,0 +1,140 @@
+package onepassword
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_lookupItem(t *testing.T) {
+	list := `[
+		{ "uuid": "login1", "templateUUID": "001", "overview": { "title": "db", "tags": ["billing"], "url": "https://db.example.com" } },
+		{ "uuid": "login2", "templateUUID": "001", "overview": { "title": "db", "tags": ["search"], "URLs": [{ "u": "https://search.example.com" }] } },
+		{ "uuid": "note1", "templateUUID": "003", "overview": { "title": "db", "tags": ["billing"] } }
+	]`
+	details := map[string]string{
+		"login1": `{ "uuid": "login1", "templateUUID": "001", "details": { "sections": [{ "title": "owner", "fields": [{ "k": "string", "t": "team", "v": "billing" }] }] } }`,
+		"login2": `{ "uuid": "login2", "templateUUID": "001", "details": { "sections": [{ "title": "owner", "fields": [{ "k": "string", "t": "team", "v": "search" }] }] } }`,
+	}
+	tests := []struct {
+		name      string
+		raw       map[string]interface{}
+		want      string
+		wantReads int
+		wantErr   string
+	}{
+		{
+			name:      "tag",
+			raw:       map[string]interface{}{"tag": "billing"},
+			want:      "login1",
+			wantReads: 1,
+		},
+		{
+			name:      "url",
+			raw:       map[string]interface{}{"url": "https://search.example.com"},
+			want:      "login2",
+			wantReads: 1,
+		},
+		{
+			name: "field match",
+			raw: map[string]interface{}{"field_match": []interface{}{
+				map[string]interface{}{"section": "owner", "field": "team", "value": "search"},
+			}},
+			want:      "login2",
+			wantReads: 2,
+		},
+		{
+			name: "field match narrowed by tag",
+			raw: map[string]interface{}{"tag": "search", "field_match": []interface{}{
+				map[string]interface{}{"section": "owner", "field": "team", "value": "search"},
+			}},
+			want:      "login2",
+			wantReads: 1,
+		},
+		{
+			name:      "name and tag",
+			raw:       map[string]interface{}{"name": "db", "tag": "billing"},
+			want:      "login1",
+			wantReads: 1,
+		},
+		{
+			name:    "ambiguous",
+			raw:     map[string]interface{}{"name": "db"},
+			wantErr: "2 items match",
+		},
+		{
+			name: "field match without section",
+			raw: map[string]interface{}{"field_match": []interface{}{
+				map[string]interface{}{"field": "team", "value": "search"},
+			}},
+			wantErr: "no item matches",
+		},
+		{
+			name:    "no match",
+			raw:     map[string]interface{}{"tag": "unknown"},
+			wantErr: "no item matches",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reads := 0
+			config := &mockOnePassConfig{}
+			config.runCmd = func() (string, error) {
+				args := config.execCommandResults
+				if args[1] == opPasswordList {
+					return strings.Join(strings.Fields(list), " "), nil
+				}
+				reads++
+				return details[args[3]], nil
+			}
+			d := schema.TestResourceDataRaw(t, dataSourceItemLogin().Schema, tt.raw)
+
+			got, err := lookupItem(context.Background(), mockOnePassClient(config), d, []Category{LoginCategory})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("lookupItem() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.UUID != tt.want {
+				t.Errorf("lookupItem() = %s, want %s", got.UUID, tt.want)
+			}
+			if got.Details.Sections == nil {
+				t.Errorf("lookupItem() returned the item without details")
+			}
+			if reads != tt.wantReads {
+				t.Errorf("lookupItem() read %d items, want %d", reads, tt.wantReads)
+			}
+		})
+	}
+}
+
+func Test_dataSourceItemRead(t *testing.T) {
+	reads := 0
+	config := &mockOnePassConfig{
+		runCmd: func() (string, error) {
+			reads++
+			return `{ "uuid": "login1", "templateUUID": "001", "overview": { "title": "db" }, "details": { "fields": [{ "type": "P", "designation": "password", "name": "password", "value": "secret" }] } }`, nil
+		},
+	}
+	m := &Meta{onePassClient: mockOnePassClient(config)}
+	d := schema.TestResourceDataRaw(t, dataSourceItemLogin().Schema, map[string]interface{}{"name": "db"})
+
+	if diags := dataSourceItemLogin().ReadContext(context.Background(), d, m); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() != "login1" {
+		t.Errorf("dataSourceItemRead() id = %s, want login1", d.Id())
+	}
+	if password := d.Get("fields").(map[string]interface{})["password"]; password != "secret" {
+		t.Errorf("dataSourceItemRead() fields.password = %v, want secret", password)
+	}
+	if reads != 1 {
+		t.Errorf("dataSourceItemRead() ran op %d times, want 1", reads)
+	}
+}
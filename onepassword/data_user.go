@@ -33,10 +33,13 @@ func dataSourceUser() *schema.Resource {
 
 func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	m := meta.(*Meta)
-	v, err := m.onePassClient.ReadUser(getIDEmail(d))
+	v, err := m.onePassClient.ReadUser(ctx, getIDEmail(d))
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	if v == nil {
+		return diag.Errorf("user %s not found", getIDEmail(d))
+	}
 
 	d.SetId(v.UUID)
 	if err := d.Set("email", v.Email); err != nil {
@@ -3,8 +3,11 @@ package onepassword
 import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 func dataSourceVault() *schema.Resource {
+	s := resourceVault().Schema
+	delete(s, "deletion_protection")
+
 	return &schema.Resource{
 		ReadContext: resourceVaultRead,
-		Schema:      resourceVault().Schema,
+		Schema:      s,
 	}
 }
@@ -0,0 +1,127 @@
+package onepassword
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	dateLayout      = "2006-01-02"
+	monthYearLayout = "2006-01"
+)
+
+var (
+	unixTimestamp    = regexp.MustCompile(`^-?[0-9]+$`)
+	ambiguousDate    = regexp.MustCompile(`^([0-9]{4}|[0-9]{8})$`)
+	legacyMonthYear  = regexp.MustCompile(`^[0-9]{6}$`)
+	errEmptyDateTime = errors.New("value is empty")
+)
+
+// ParseDate returns the unix timestamp 1Password stores for a date field. It accepts
+// RFC 3339 dates like 2006-01-02, RFC 3339 timestamps and unix timestamps.
+// Four and eight digit numbers are rejected, they're most likely a year or a
+// YYYYMMDD date rather than a timestamp in 1970.
+func ParseDate(s string) (int, error) {
+	switch {
+	case s == "":
+		return 0, errEmptyDateTime
+	case ambiguousDate.MatchString(s):
+		return 0, fmt.Errorf("%s is ambiguous, use values like 2006-01-02 for dates or a unix timestamp with at least 9 digits", s)
+	case unixTimestamp.MatchString(s):
+		return strconv.Atoi(s)
+	}
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return int(t.Unix()), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not a date, use values like 2006-01-02 or 2006-01-02T15:04:05Z", s)
+	}
+	return int(t.Unix()), nil
+}
+
+// FormatDate returns the date of the unix timestamp, the time is only added when it isn't midnight UTC
+func FormatDate(ts int) string {
+	if ts == 0 {
+		return ""
+	}
+	t := time.Unix(int64(ts), 0).UTC()
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format(dateLayout)
+	}
+	return t.Format(time.RFC3339)
+}
+
+// ParseMonthYear returns the YYYYMM integer 1Password stores for a month year field,
+// it accepts 2006-01 and the integer itself
+func ParseMonthYear(s string) (int, error) {
+	if s == "" {
+		return 0, errEmptyDateTime
+	}
+	if legacyMonthYear.MatchString(s) {
+		s = s[:4] + "-" + s[4:]
+	}
+	t, err := time.Parse(monthYearLayout, s)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not a month, use values like 2006-01", s)
+	}
+	return t.Year()*100 + int(t.Month()), nil
+}
+
+// FormatMonthYear returns the YYYYMM integer as 2006-01
+func FormatMonthYear(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%04d-%02d", v/100, v%100)
+}
+
+// normalizeDate is the StateFunc of date attributes, values which can't be parsed are kept
+// as they are and rejected by the validation
+func normalizeDate(v interface{}) string {
+	s, _ := v.(string)
+	if ts, err := ParseDate(s); err == nil {
+		return FormatDate(ts)
+	}
+	return s
+}
+
+// normalizeMonthYear is the StateFunc of month year attributes
+func normalizeMonthYear(v interface{}) string {
+	s, _ := v.(string)
+	if my, err := ParseMonthYear(s); err == nil {
+		return FormatMonthYear(my)
+	}
+	return s
+}
+
+// dateTimeValue converts the field value read from 1Password, a JSON number, to an int
+func dateTimeValue(v interface{}) int {
+	switch val := v.(type) {
+	case float64:
+		return int(val)
+	case int:
+		return val
+	case string:
+		i, _ := strconv.Atoi(val)
+		return i
+	}
+	return 0
+}
+
+// dateValue returns the unix timestamp of the date attribute, 0 when it isn't set
+func dateValue(v interface{}) int {
+	s, _ := v.(string)
+	ts, _ := ParseDate(s)
+	return ts
+}
+
+// monthYearValue returns the YYYYMM integer of the month year attribute, 0 when it isn't set
+func monthYearValue(v interface{}) int {
+	s, _ := v.(string)
+	my, _ := ParseMonthYear(s)
+	return my
+}
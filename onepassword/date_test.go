@@ -0,0 +1,68 @@
+package onepassword
+
+import "testing"
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		format  string
+		wantErr bool
+	}{
+		{value: "1988-03-29", want: 575596800, format: "1988-03-29"},
+		{value: "1988-03-29T12:01:00+02:00", want: 575632860, format: "1988-03-29T10:01:00Z"},
+		{value: "575553660", want: 575553660, format: "1988-03-28T12:01:00Z"},
+		{value: "19880329", wantErr: true},
+		{value: "1988", wantErr: true},
+		{value: "29.03.1988", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDate(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDate() = %d, want %d", got, tt.want)
+			}
+			if tt.wantErr {
+				return
+			}
+			if format := FormatDate(got); format != tt.format {
+				t.Errorf("FormatDate() = %s, want %s", format, tt.format)
+			}
+			if normalized := normalizeDate(tt.value); normalized != tt.format {
+				t.Errorf("normalizeDate() = %s, want %s", normalized, tt.format)
+			}
+		})
+	}
+}
+
+func TestParseMonthYear(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "2022-05", want: 202205},
+		{value: "202205", want: 202205},
+		{value: "2022-13", wantErr: true},
+		{value: "05/2022", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseMonthYear(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMonthYear() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMonthYear() = %d, want %d", got, tt.want)
+			}
+			if !tt.wantErr && FormatMonthYear(got) != "2022-05" {
+				t.Errorf("FormatMonthYear() = %s, want 2022-05", FormatMonthYear(got))
+			}
+		})
+	}
+}
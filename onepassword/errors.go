@@ -0,0 +1,137 @@
+package onepassword
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when the requested object doesn't exist in 1Password
+	ErrNotFound = errors.New("not found")
+
+	// ErrUnauthorized is returned when the account is not allowed to perform the operation
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrSessionExpired is returned when the session token is invalid or expired
+	ErrSessionExpired = errors.New("session expired")
+
+	// ErrRateLimited is returned when 1Password throttles the requests
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrConflict is returned when the object was changed concurrently or already exists
+	ErrConflict = errors.New("conflict")
+)
+
+// errorPatterns maps op output fragments to the error kinds, checked in order.
+// HTTP status codes come first as the message after them is free text,
+// e.g. "(401) Unauthorized: Authentication required".
+var errorPatterns = []struct {
+	kind      error
+	fragments []string
+}{
+	{ErrNotFound, []string{"(404)"}},
+	{ErrUnauthorized, []string{"(401)", "(403)"}},
+	{ErrRateLimited, []string{"(429)"}},
+	{ErrConflict, []string{"(409)"}},
+	{ErrNotFound, []string{
+		"isn't an item",
+		"isn't a vault",
+		"isn't a group",
+		"isn't a user",
+		"doesn't seem to be",
+		"requested resource was not found",
+	}},
+	{ErrSessionExpired, []string{
+		"session expired",
+		"invalid session token",
+		"not currently signed in",
+		"authentication required",
+	}},
+	{ErrUnauthorized, []string{
+		"unauthorized",
+		"forbidden",
+	}},
+	{ErrRateLimited, []string{
+		"too many requests",
+		"rate limit",
+	}},
+	{ErrConflict, []string{
+		"conflict",
+		"already exists",
+	}},
+}
+
+// transientPatterns are op output fragments of failures which usually go away on retry
+var transientPatterns = []string{
+	"(500)",
+	"(502)",
+	"(503)",
+	"(504)",
+	"connection reset",
+	"connection refused",
+	"i/o timeout",
+	"tls handshake timeout",
+	"unexpected eof",
+}
+
+// CommandError describes a failed op invocation
+type CommandError struct {
+	Args   []string
+	Output []byte
+	Err    error
+	// Kind is one of the Err* values or nil when the failure is unknown
+	Kind error
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("some error in command %v\nError: %s\nOutput: %s", e.Args, e.Err, e.Output)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrNotFound) and friends work for command errors
+func (e *CommandError) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+func classifyError(res []byte) error {
+	out := strings.ToLower(string(res))
+	for _, p := range errorPatterns {
+		for _, fragment := range p.fragments {
+			if strings.Contains(out, fragment) {
+				return p.kind
+			}
+		}
+	}
+	return nil
+}
+
+// idempotentCommands are the op subcommands which can be repeated safely
+// when it's unknown whether the failed attempt reached 1Password
+var idempotentCommands = []string{
+	opPasswordGet,
+	opPasswordList,
+	opPasswordDelete,
+}
+
+// isRetryable reports whether the failed op invocation is worth another attempt.
+// Rate limited requests were rejected and are always retried, server and network
+// errors only for idempotent subcommands, as a create or edit may have been applied.
+func isRetryable(args []string, res []byte) bool {
+	if classifyError(res) == ErrRateLimited {
+		return true
+	}
+	if len(args) == 0 || !stringInSlice(args[0], idempotentCommands) {
+		return false
+	}
+	out := strings.ToLower(string(res))
+	for _, fragment := range transientPatterns {
+		if strings.Contains(out, fragment) {
+			return true
+		}
+	}
+	return false
+}
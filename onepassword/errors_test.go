@@ -0,0 +1,71 @@
+package onepassword
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func Test_prettyError(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   error
+	}{
+		{
+			name:   "item not found",
+			output: `[ERROR] 2020/08/10 12:00:00 "foo" isn't an item in the "bar" vault. Specify the item with its UUID, name, or domain.`,
+			want:   ErrNotFound,
+		},
+		{
+			name:   "vault not found",
+			output: `[ERROR] 2020/08/10 12:00:00 "bar" isn't a vault in this account. Specify the vault with its UUID or name.`,
+			want:   ErrNotFound,
+		},
+		{
+			name:   "session expired",
+			output: `[ERROR] 2020/08/10 12:00:00 You are not currently signed in. Please run ` + "`op signin --help`" + ` for instructions`,
+			want:   ErrSessionExpired,
+		},
+		{
+			name:   "unauthorized",
+			output: `[ERROR] 2020/08/10 12:00:00 (401) Unauthorized: You aren't authorized to perform this action.`,
+			want:   ErrUnauthorized,
+		},
+		{
+			name:   "unauthorized signin",
+			output: `[ERROR] 2020/08/10 12:00:00 (401) Unauthorized: Authentication required.`,
+			want:   ErrUnauthorized,
+		},
+		{
+			name:   "session token rejected",
+			output: `[ERROR] 2020/08/10 12:00:00 Authentication required.`,
+			want:   ErrSessionExpired,
+		},
+		{
+			name:   "rate limited",
+			output: `[ERROR] 2020/08/10 12:00:00 (429) Too Many Requests`,
+			want:   ErrRateLimited,
+		},
+		{
+			name:   "conflict",
+			output: `[ERROR] 2020/08/10 12:00:00 (409) Conflict: The item was changed.`,
+			want:   ErrConflict,
+		},
+	}
+	sentinels := []error{ErrNotFound, ErrUnauthorized, ErrSessionExpired, ErrRateLimited, ErrConflict}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := prettyError([]string{"get", "item", "foo"}, []byte(tt.output), &exec.ExitError{})
+			for _, sentinel := range sentinels {
+				if errors.Is(err, sentinel) != (sentinel == tt.want) {
+					t.Errorf("errors.Is(prettyError(), %v) = %v, want %v", sentinel, !(sentinel == tt.want), sentinel == tt.want)
+				}
+			}
+		})
+	}
+
+	if err := prettyError([]string{"get", "item", "foo"}, []byte("oops"), errors.New("exit status 1")); errors.Is(err, ErrNotFound) {
+		t.Error("unknown output was classified as not found")
+	}
+}
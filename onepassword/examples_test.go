@@ -0,0 +1,355 @@
+package onepassword
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+)
+
+const examplesDir = "../examples"
+
+// exampleVars are the values of example variables without a default, vault_id is set per test
+var exampleVars = map[string]string{
+	"email":       "example@example.com",
+	"login":       "anasinnyk",
+	"password":    "Jm8e!2pQz#41",
+	"website":     "https://terraform.io",
+	"secret":      "very secret note",
+	"license_key": "ABCD-1234-EFGH-5678",
+}
+
+// exampleImportVerifyIgnore lists the arguments import can't restore, because they only live in Terraform
+var exampleImportVerifyIgnore = map[string][]string{
+	"onepassword_item_common":           {"archive_on_destroy", "deletion_protection"},
+	"onepassword_item_credit_card":      {"archive_on_destroy", "deletion_protection"},
+	"onepassword_item_document":         {"archive_on_destroy", "deletion_protection", "file_path"},
+	"onepassword_item_identity":         {"archive_on_destroy", "deletion_protection"},
+	"onepassword_item_login":            {"archive_on_destroy", "deletion_protection"},
+	"onepassword_item_password":         {"archive_on_destroy", "deletion_protection"},
+	"onepassword_item_secure_note":      {"archive_on_destroy", "deletion_protection"},
+	"onepassword_item_software_license": {"archive_on_destroy", "deletion_protection"},
+	"onepassword_vault":                 {"deletion_protection"},
+}
+
+type exampleResource struct {
+	Type   string
+	Name   string
+	Config map[string]interface{}
+}
+
+// exampleDirs returns the example modules, the root module only composes them
+func exampleDirs(t *testing.T) []string {
+	entries, err := ioutil.ReadDir(examplesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs = append(dirs, e.Name())
+		}
+	}
+	return dirs
+}
+
+// exampleConfig returns the configuration of the example module as a root module,
+// variables without a default get one from vars and path.module points to the example
+func exampleConfig(t *testing.T, name string, vars map[string]string) string {
+	dir, err := filepath.Abs(filepath.Join(examplesDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+
+	var config []string
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		src = []byte(strings.ReplaceAll(string(src), "${path.module}", dir))
+		f, diags := hclwrite.ParseConfig(src, file, hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		for _, block := range f.Body().Blocks() {
+			if block.Type() != "variable" || block.Body().GetAttribute("default") != nil {
+				continue
+			}
+			if v, ok := vars[block.Labels()[0]]; ok {
+				block.Body().SetAttributeValue("default", cty.StringVal(v))
+			}
+		}
+		config = append(config, string(f.Bytes()))
+	}
+	return strings.Join(config, "\n")
+}
+
+// exampleResources evaluates the resources of the configuration to the raw configuration
+// the provider receives for them
+func exampleResources(t *testing.T, config string) []exampleResource {
+	f, diags := hclsyntax.ParseConfig([]byte(config), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	body := f.Body.(*hclsyntax.Body)
+
+	vars := map[string]cty.Value{}
+	for _, block := range body.Blocks {
+		if block.Type != "variable" {
+			continue
+		}
+		if attr, ok := block.Body.Attributes["default"]; ok {
+			v, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			vars[block.Labels[0]] = v
+		}
+	}
+	ctx := &hcl.EvalContext{Variables: map[string]cty.Value{"var": cty.ObjectVal(vars)}}
+
+	var resources []exampleResource
+	for _, block := range body.Blocks {
+		if block.Type == "resource" {
+			resources = append(resources, exampleResource{
+				Type:   block.Labels[0],
+				Name:   block.Labels[1],
+				Config: exampleBody(t, block.Body, ctx),
+			})
+		}
+	}
+	return resources
+}
+
+func exampleBody(t *testing.T, body *hclsyntax.Body, ctx *hcl.EvalContext) map[string]interface{} {
+	raw := map[string]interface{}{}
+	for name, attr := range body.Attributes {
+		v, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		raw[name] = exampleValue(t, v)
+	}
+	for _, block := range body.Blocks {
+		list, _ := raw[block.Type].([]interface{})
+		raw[block.Type] = append(list, exampleBody(t, block.Body, ctx))
+	}
+	return raw
+}
+
+func exampleValue(t *testing.T, v cty.Value) interface{} {
+	switch {
+	case v.Type() == cty.String:
+		return v.AsString()
+	case v.Type() == cty.Bool:
+		return v.True()
+	case v.Type() == cty.Number:
+		var i int
+		if err := gocty.FromCtyValue(v, &i); err == nil {
+			return i
+		}
+		var f float64
+		if err := gocty.FromCtyValue(v, &f); err != nil {
+			t.Fatal(err)
+		}
+		return f
+	case v.Type().IsTupleType() || v.Type().IsListType():
+		var list []interface{}
+		for it := v.ElementIterator(); it.Next(); {
+			_, e := it.Element()
+			list = append(list, exampleValue(t, e))
+		}
+		return list
+	case v.Type().IsObjectType() || v.Type().IsMapType():
+		m := map[string]interface{}{}
+		for it := v.ElementIterator(); it.Next(); {
+			k, e := it.Element()
+			m[k.AsString()] = exampleValue(t, e)
+		}
+		return m
+	}
+	t.Fatalf("unsupported value type %s", v.Type().FriendlyName())
+	return nil
+}
+
+// newExampleFake returns a fake account with the objects the data sources of the examples look up
+func newExampleFake(t *testing.T) (*fakeOP, *Vault) {
+	f := newFakeOP(t)
+	f.AddVault("Personal")
+	f.AddGroup("Team Members")
+	f.AddUser("example@example.com", "John", "Smith")
+	return f, f.AddVault("examples")
+}
+
+// TestExamples_roundTrip creates the resources of every example against the fake account
+// and checks that neither a refresh nor an import leads to changes
+func TestExamples_roundTrip(t *testing.T) {
+	for _, name := range exampleDirs(t) {
+		t.Run(name, func(t *testing.T) {
+			f, vault := newExampleFake(t)
+			vars := map[string]string{"vault_id": vault.UUID}
+			for k, v := range exampleVars {
+				vars[k] = v
+			}
+			meta := &Meta{
+				data:          schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{}),
+				onePassClient: f.client(),
+			}
+			for _, res := range exampleResources(t, exampleConfig(t, name, vars)) {
+				testRoundTrip(t, meta, res)
+			}
+		})
+	}
+}
+
+// testRoundTrip creates the resource, the refreshed state has to match the configuration
+// and importing the resource has to lead to the same state
+func testRoundTrip(t *testing.T, meta *Meta, res exampleResource) {
+	ctx := context.Background()
+	r := Provider().ResourcesMap[res.Type]
+	config := terraform.NewResourceConfigRaw(res.Config)
+	if diags := r.Validate(config); diags.HasError() {
+		t.Fatalf("%s.%s is not valid: %v", res.Type, res.Name, diags)
+	}
+
+	d := schema.TestResourceDataRaw(t, r.Schema, res.Config)
+	if diags := r.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create %s.%s: %v", res.Type, res.Name, diags)
+	}
+	state, diags := r.RefreshWithoutUpgrade(ctx, d.State(), meta)
+	if diags.HasError() || state == nil {
+		t.Fatalf("refresh %s.%s: %v", res.Type, res.Name, diags)
+	}
+	diff, err := r.Diff(ctx, state, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() {
+		t.Errorf("plan of %s.%s after apply isn't empty: %v", res.Type, res.Name, diff.Attributes)
+	}
+
+	if r.Importer == nil {
+		return
+	}
+	imported, err := r.Importer.StateContext(ctx, r.Data(&terraform.InstanceState{ID: state.ID}), meta)
+	if err != nil {
+		t.Fatalf("import %s.%s: %v", res.Type, res.Name, err)
+	}
+	importedState, diags := r.RefreshWithoutUpgrade(ctx, imported[0].State(), meta)
+	if diags.HasError() || importedState == nil {
+		t.Fatalf("refresh imported %s.%s: %v", res.Type, res.Name, diags)
+	}
+	ignore := append([]string{"%", "timeouts"}, exampleImportVerifyIgnore[res.Type]...)
+	for k, v := range state.Attributes {
+		if !ignored(k, ignore) && importedState.Attributes[k] != v {
+			t.Errorf("imported %s.%s has %s = %q, want %q", res.Type, res.Name, k, importedState.Attributes[k], v)
+		}
+	}
+}
+
+func ignored(key string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if key == p || strings.HasPrefix(key, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// TestAccExamples applies every example with Terraform against the fake account,
+// the plan has to be empty after apply and imports have to match the state
+func TestAccExamples(t *testing.T) {
+	factories := testAccProviderFactories(t)
+	for _, name := range exampleDirs(t) {
+		t.Run(name, func(t *testing.T) {
+			f, vault := newExampleFake(t)
+			vars := map[string]string{"vault_id": vault.UUID}
+			for k, v := range exampleVars {
+				vars[k] = v
+			}
+			config := fakeOPProviderConfig(t, f) + exampleConfig(t, name, vars)
+			steps := []resource.TestStep{{Config: config}}
+			for _, res := range exampleResources(t, config) {
+				if Provider().ResourcesMap[res.Type].Importer == nil {
+					continue
+				}
+				steps = append(steps, resource.TestStep{
+					Config:                  config,
+					ResourceName:            res.Type + "." + res.Name,
+					ImportState:             true,
+					ImportStateVerify:       true,
+					ImportStateVerifyIgnore: exampleImportVerifyIgnore[res.Type],
+				})
+			}
+			resource.UnitTest(t, resource.TestCase{
+				ProviderFactories: factories,
+				Steps:             steps,
+			})
+		})
+	}
+}
+
+func TestSectionFields_roundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		field map[string]interface{}
+	}{
+		{name: "string", field: map[string]interface{}{"name": "host", "string": "db.example.com"}},
+		{name: "url", field: map[string]interface{}{"name": "console", "url": "https://console.example.com"}},
+		{name: "phone", field: map[string]interface{}{"name": "support", "phone": "+38 (000) 000 0000"}},
+		{name: "email", field: map[string]interface{}{"name": "owner", "email": "owner@example.com"}},
+		{name: "sex", field: map[string]interface{}{"name": "sex", "sex": "female"}},
+		{name: "card type", field: map[string]interface{}{"name": "type", "card_type": "visa"}},
+		{name: "date", field: map[string]interface{}{"name": "since", "date": "1988-03-29"}},
+		{name: "date time", field: map[string]interface{}{"name": "since", "date": "1988-03-29T12:01:00+02:00"}},
+		{name: "unix timestamp", field: map[string]interface{}{"name": "since", "date": 575553660}},
+		{name: "month year", field: map[string]interface{}{"name": "expires", "month_year": "2022-05"}},
+		{name: "legacy month year", field: map[string]interface{}{"name": "expires", "month_year": 202205}},
+		{name: "concealed", field: map[string]interface{}{"name": "pin", "concealed": "1234"}},
+		{name: "totp secret", field: map[string]interface{}{"name": "otp", "totp": "JBSWY3DPEHPK3PXP"}},
+		{name: "totp uri", field: map[string]interface{}{"name": "otp", "totp": "otpauth://totp/example?secret=JBSWY3DPEHPK3PXP"}},
+		{name: "address", field: map[string]interface{}{"name": "office", "address": []interface{}{map[string]interface{}{
+			"city": "Kyiv", "country": "ua", "street": "11 Line", "zip": "46000",
+		}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeOP(t)
+			vault := f.AddVault("examples")
+			meta := &Meta{
+				data:          schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{}),
+				onePassClient: f.client(),
+			}
+			testRoundTrip(t, meta, exampleResource{
+				Type: "onepassword_item_login",
+				Name: "this",
+				Config: map[string]interface{}{
+					"name":     "fields",
+					"vault":    vault.UUID,
+					"username": "admin",
+					"section": []interface{}{map[string]interface{}{
+						"name":  "extra",
+						"field": []interface{}{tt.field},
+					}},
+				},
+			})
+		})
+	}
+}
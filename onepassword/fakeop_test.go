@@ -0,0 +1,1019 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/kalaspuffar/base64url"
+)
+
+// fakeOP is an in-memory 1Password account answering op commands, both in the v1 shape
+// used by the provider ("get item foo") and in the v2 shape ("item get foo").
+//
+// The op process is the test binary itself running TestFakeOPHelperProcess, which forwards
+// its arguments and stdin to the account served over HTTP by the test, so the state is
+// shared by every op invocation of the test.
+type fakeOP struct {
+	// Version is printed by op --version
+	Version string
+	// Password is the master password accepted by op signin
+	Password string
+
+	mu        sync.Mutex
+	server    *httptest.Server
+	nextID    int
+	session   string
+	vaults    map[string]*Vault
+	items     map[string]*Item
+	documents map[string][]byte
+	groups    map[string]*Group
+	users     map[string]*User
+	members   map[string]map[string]bool
+	calls     [][]string
+}
+
+type fakeOPRequest struct {
+	Args    []string
+	Stdin   []byte
+	Session string
+}
+
+type fakeOPResponse struct {
+	Output []byte
+	Code   int
+}
+
+const fakeOPAddrPrefix = "fakeop="
+
+func newFakeOP(t *testing.T) *fakeOP {
+	f := &fakeOP{
+		Version:   "1.12.0",
+		Password:  "master password",
+		vaults:    map[string]*Vault{},
+		items:     map[string]*Item{},
+		documents: map[string][]byte{},
+		groups:    map[string]*Group{},
+		users:     map[string]*User{},
+		members:   map[string]map[string]bool{},
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &fakeOPRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		out, code := f.run(req)
+		if err := json.NewEncoder(w).Encode(&fakeOPResponse{Output: out, Code: code}); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// TestFakeOPHelperProcess isn't a real test, it's the op process of fakeOP
+func TestFakeOPHelperProcess(t *testing.T) {
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	if len(args) < 2 || !strings.HasPrefix(args[1], fakeOPAddrPrefix) {
+		return
+	}
+
+	req := &fakeOPRequest{Args: args[2:]}
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, "OP_SESSION_") {
+			req.Session = env[strings.Index(env, "=")+1:]
+		}
+	}
+	// signin reads the secret key and the password from stdin, every other command only when its argument is "-"
+	if stringInSlice(Stdin, req.Args) || (len(req.Args) > 0 && req.Args[0] == "signin") {
+		req.Stdin, _ = ioutil.ReadAll(os.Stdin)
+	}
+	body, _ := json.Marshal(req)
+	res, err := http.Post("http://"+strings.TrimPrefix(args[1], fakeOPAddrPrefix), "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	out := &fakeOPResponse{}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	os.Stdout.Write(out.Output)
+	os.Exit(out.Code)
+}
+
+func (f *fakeOP) helperArgs(args ...string) []string {
+	return append([]string{"-test.run=^TestFakeOPHelperProcess$", "--", fakeOPAddrPrefix + f.server.Listener.Addr().String()}, args...)
+}
+
+// client returns a client signed in to the fake account
+func (f *fakeOP) client() *OnePassClient {
+	f.mu.Lock()
+	f.session = "fake-session"
+	f.mu.Unlock()
+	return &OnePassClient{
+		Address:  "my.1password.com",
+		Account:  "my",
+		PathToOp: os.Args[0],
+		Session:  "fake-session",
+		execCommand: func(ctx context.Context, bin string, args ...string) *exec.Cmd {
+			return exec.CommandContext(ctx, bin, f.helperArgs(args...)...)
+		},
+		mutex: &sync.Mutex{},
+	}
+}
+
+// binary writes an op executable running the fake, for op_binary_path of provider configurations
+func (f *fakeOP) binary(t *testing.T) string {
+	bin := filepath.Join(t.TempDir(), "op")
+	script := fmt.Sprintf("#!/bin/sh\nexec %q %s \"$@\"\n", os.Args[0], strings.Join(f.helperArgs(), " "))
+	if err := ioutil.WriteFile(bin, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return bin
+}
+
+// Calls returns the arguments of every op invocation so far
+func (f *fakeOP) Calls() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string{}, f.calls...)
+}
+
+// AddVault, AddUser, AddGroup and AddItem seed the account
+func (f *fakeOP) AddVault(name string) *Vault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v := &Vault{UUID: f.id("vault"), Name: name}
+	f.vaults[v.UUID] = v
+	return v
+}
+
+func (f *fakeOP) AddUser(email, firstName, lastName string) *User {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// op prints user UUIDs in upper case
+	u := &User{UUID: strings.ToUpper(f.id("user")), Email: email, FirstName: firstName, LastName: lastName, State: UserStateActive}
+	f.users[u.UUID] = u
+	return u
+}
+
+func (f *fakeOP) AddGroup(name string) *Group {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g := &Group{UUID: f.id("group"), Name: name, State: GroupStateActive}
+	f.groups[g.UUID] = g
+	f.members[g.UUID] = map[string]bool{}
+	return g
+}
+
+func (f *fakeOP) AddItem(v Item) *Item {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	item := v
+	item.UUID = f.id("item")
+	if item.Trashed == "" {
+		item.Trashed = IsNotTrashed
+	}
+	f.items[item.UUID] = &item
+	return &item
+}
+
+// Item returns a copy of the stored item, nil when it doesn't exist
+func (f *fakeOP) Item(id string) *Item {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if v, ok := f.items[id]; ok {
+		item := *v
+		return &item
+	}
+	return nil
+}
+
+// id returns a new 26 characters identifier like the ones of 1Password
+func (f *fakeOP) id(kind string) string {
+	f.nextID++
+	return fmt.Sprintf("%s%0*d", kind, 26-len(kind), f.nextID)
+}
+
+func (f *fakeOP) run(req *fakeOPRequest) ([]byte, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, req.Args)
+
+	cmd := parseFakeOPArgs(req.Args)
+	switch {
+	case cmd.flags["version"] != "":
+		return []byte(f.Version + "\n"), 0
+	case cmd.verb == "signin":
+		return f.signin(req)
+	case f.session == "" || req.Session != f.session:
+		return fakeOPError("You are not currently signed in. Please run `op signin --help` for instructions")
+	}
+	cmd.stdin = req.Stdin
+
+	switch cmd.noun {
+	case VaultResource:
+		return f.vault(cmd)
+	case ItemResource, "items":
+		return f.item(cmd)
+	case DocumentResource:
+		return f.document(cmd)
+	case GroupResource:
+		return f.group(cmd)
+	case UserResource, "users":
+		return f.user(cmd)
+	case "member":
+		return f.member(cmd)
+	}
+	return fakeOPError(fmt.Sprintf("unknown command %s %s", cmd.verb, cmd.noun))
+}
+
+type fakeOPCommand struct {
+	verb  string
+	noun  string
+	args  []string
+	flags map[string]string
+	stdin []byte
+	v2    bool
+}
+
+// fakeOPValueFlags take a value from the next argument in the v2 shape, e.g. --vault ops
+var fakeOPValueFlags = map[string]bool{
+	"vault": true, "format": true, "category": true, "title": true, "url": true, "tags": true,
+	"group": true, "user": true, "name": true, "file-name": true, "config": true,
+}
+
+var fakeOPNouns = map[string]bool{
+	VaultResource: true, ItemResource: true, DocumentResource: true, GroupResource: true, UserResource: true,
+}
+
+func parseFakeOPArgs(args []string) *fakeOPCommand {
+	cmd := &fakeOPCommand{flags: map[string]string{}}
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+		name := strings.TrimPrefix(arg, "--")
+		if spl := strings.SplitN(name, "=", 2); len(spl) == 2 {
+			cmd.flags[spl[0]] = spl[1]
+		} else if fakeOPValueFlags[name] && i+1 < len(args) {
+			cmd.flags[name] = args[i+1]
+			i++
+		} else {
+			cmd.flags[name] = "true"
+		}
+	}
+	if len(positional) == 0 {
+		return cmd
+	}
+	cmd.verb = positional[0]
+	if len(positional) > 1 {
+		cmd.noun = positional[1]
+		cmd.args = positional[2:]
+	}
+	if fakeOPNouns[positional[0]] && len(positional) > 1 {
+		// v2: op item get foo, op group user grant --group g --user u
+		cmd.v2 = true
+		cmd.noun, cmd.verb, cmd.args = positional[0], positional[1], positional[2:]
+		if cmd.noun == GroupResource && cmd.verb == UserResource && len(cmd.args) > 0 {
+			cmd.verb, cmd.args = cmd.args[0], cmd.args[1:]
+			cmd.noun = "member"
+		}
+	}
+	return cmd
+}
+
+func fakeOPError(msg string) ([]byte, int) {
+	return []byte("[ERROR] 2020/08/10 12:00:00 " + msg + "\n"), 1
+}
+
+func fakeOPJSON(v interface{}) ([]byte, int) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fakeOPError(err.Error())
+	}
+	return append(b, '\n'), 0
+}
+
+func (c *fakeOPCommand) arg(i int) string {
+	if i < len(c.args) {
+		return c.args[i]
+	}
+	return ""
+}
+
+func (f *fakeOP) signin(req *fakeOPRequest) ([]byte, int) {
+	// op prompts for the secret key first, then for the password
+	answers := strings.Split(strings.TrimSpace(string(req.Stdin)), "\n")
+	if len(answers) != 2 || answers[0] == "" || answers[1] != f.Password {
+		return fakeOPError("(401) Unauthorized: Authentication required.")
+	}
+	f.session = "fake-session"
+	return []byte(f.session + "\n"), 0
+}
+
+func (f *fakeOP) findVault(id string) *Vault {
+	if v, ok := f.vaults[id]; ok {
+		return v
+	}
+	for _, v := range f.vaults {
+		if v.Name == id {
+			return v
+		}
+	}
+	return nil
+}
+
+func (f *fakeOP) vault(cmd *fakeOPCommand) ([]byte, int) {
+	switch cmd.verb {
+	case opPasswordCreate:
+		v := &Vault{UUID: f.id("vault"), Name: cmd.arg(0)}
+		f.vaults[v.UUID] = v
+		return fakeOPJSON(v)
+	case opPasswordList:
+		vaults := make([]*Vault, 0, len(f.vaults))
+		for _, v := range f.vaults {
+			vaults = append(vaults, v)
+		}
+		sort.Slice(vaults, func(i, j int) bool { return vaults[i].UUID < vaults[j].UUID })
+		return fakeOPJSON(vaults)
+	}
+	v := f.findVault(cmd.arg(0))
+	if v == nil {
+		return fakeOPError(fmt.Sprintf("%q isn't a vault in this account. Specify the vault with its UUID or name.", cmd.arg(0)))
+	}
+	switch cmd.verb {
+	case opPasswordGet:
+		return fakeOPJSON(v)
+	case opPasswordEdit:
+		if name := cmd.flags["name"]; name != "" {
+			v.Name = name
+		}
+		return nil, 0
+	case opPasswordDelete:
+		for id, item := range f.items {
+			if item.Vault == v.UUID {
+				delete(f.items, id)
+				delete(f.documents, id)
+			}
+		}
+		delete(f.vaults, v.UUID)
+		return nil, 0
+	}
+	return fakeOPError("unknown vault command " + cmd.verb)
+}
+
+// findItem looks the item up by UUID or title like op does, archived items are only found by UUID
+func (f *fakeOP) findItem(id, vault string) (*Item, []byte, int) {
+	var vaultID string
+	if vault != "" {
+		v := f.findVault(vault)
+		if v == nil {
+			out, code := fakeOPError(fmt.Sprintf("%q isn't a vault in this account. Specify the vault with its UUID or name.", vault))
+			return nil, out, code
+		}
+		vaultID = v.UUID
+	}
+	if item, ok := f.items[id]; ok && (vaultID == "" || item.Vault == vaultID) {
+		return item, nil, 0
+	}
+	var found []*Item
+	for _, item := range f.items {
+		if item.Overview.Title == id && item.Trashed != IsTrashed && (vaultID == "" || item.Vault == vaultID) {
+			found = append(found, item)
+		}
+	}
+	switch len(found) {
+	case 0:
+		out, code := fakeOPError(fmt.Sprintf("%q isn't an item. Specify the item with its UUID, name, or domain.", id))
+		return nil, out, code
+	case 1:
+		return found[0], nil, 0
+	}
+	out, code := fakeOPError(fmt.Sprintf("More than one item matches %q. Try again and specify the item by its UUID", id))
+	return nil, out, code
+}
+
+func (f *fakeOP) item(cmd *fakeOPCommand) ([]byte, int) {
+	switch cmd.verb {
+	case opPasswordCreate:
+		return f.createItem(cmd)
+	case opPasswordList:
+		return f.listItems(cmd)
+	}
+	item, out, code := f.findItem(cmd.arg(0), cmd.flags["vault"])
+	if item == nil {
+		return out, code
+	}
+	switch cmd.verb {
+	case opPasswordGet:
+		if cmd.v2 {
+			return fakeOPJSON(fakeOPItemV2(item))
+		}
+		return fakeOPJSON(item)
+	case opPasswordDelete:
+		if cmd.flags["archive"] != "" {
+			item.Trashed = IsTrashed
+			return nil, 0
+		}
+		delete(f.items, item.UUID)
+		delete(f.documents, item.UUID)
+		return nil, 0
+	}
+	return fakeOPError("unknown item command " + cmd.verb)
+}
+
+func (f *fakeOP) listItems(cmd *fakeOPCommand) ([]byte, int) {
+	var vaultID string
+	if vault := cmd.flags["vault"]; vault != "" {
+		v := f.findVault(vault)
+		if v == nil {
+			return fakeOPError(fmt.Sprintf("%q isn't a vault in this account. Specify the vault with its UUID or name.", vault))
+		}
+		vaultID = v.UUID
+	}
+	// op v1 lists archived items with --include-trash, op v2 with --include-archive
+	archived := cmd.flags["include-trash"] != "" || cmd.flags["include-archive"] != ""
+	items := []*Item{}
+	for _, item := range f.items {
+		if (archived || item.Trashed != IsTrashed) && (vaultID == "" || item.Vault == vaultID) {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].UUID < items[j].UUID })
+	if cmd.v2 {
+		res := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			res = append(res, fakeOPItemV2(item))
+		}
+		return fakeOPJSON(res)
+	}
+	// list items prints overviews only
+	res := make([]Item, 0, len(items))
+	for _, item := range items {
+		res = append(res, Item{UUID: item.UUID, Template: item.Template, Vault: item.Vault, Overview: item.Overview, Trashed: item.Trashed})
+	}
+	return fakeOPJSON(res)
+}
+
+func (f *fakeOP) createItem(cmd *fakeOPCommand) ([]byte, int) {
+	item := &Item{Trashed: IsNotTrashed}
+	if cmd.v2 {
+		if out, code := fakeOPItemFromV2(cmd, item); code != 0 {
+			return out, code
+		}
+	} else {
+		item.Template = Category2Template(Category(cmd.arg(0)))
+		payload := cmd.arg(1)
+		if payload == Stdin {
+			payload = strings.TrimSpace(string(cmd.stdin))
+		}
+		decoded, err := base64url.Decode(payload)
+		if err != nil {
+			return fakeOPError("invalid item details: " + err.Error())
+		}
+		details := struct {
+			Details
+			URLs []ItemURL `json:"URLs,omitempty"`
+		}{}
+		if err := json.Unmarshal(decoded, &details); err != nil {
+			return fakeOPError("invalid item details: " + err.Error())
+		}
+		item.Details = details.Details
+		item.Overview.URLs = details.URLs
+	}
+	if item.Template == Category2Template(UnknownCategory) {
+		return fakeOPError("unknown category")
+	}
+	item.Overview.Title = cmd.flags["title"]
+	item.Overview.URL = cmd.flags["url"]
+	if tags := cmd.flags["tags"]; tags != "" {
+		item.Overview.Tags = strings.Split(tags, ",")
+	}
+	if out, code := f.placeItem(cmd, item); code != 0 {
+		return out, code
+	}
+	return fakeOPJSON(map[string]string{"uuid": item.UUID, "vaultUuid": item.Vault})
+}
+
+// placeItem stores the item in the vault of the command or the first vault of the account
+func (f *fakeOP) placeItem(cmd *fakeOPCommand, item *Item) ([]byte, int) {
+	if vault := cmd.flags["vault"]; vault != "" {
+		v := f.findVault(vault)
+		if v == nil {
+			return fakeOPError(fmt.Sprintf("%q isn't a vault in this account. Specify the vault with its UUID or name.", vault))
+		}
+		item.Vault = v.UUID
+	} else {
+		ids := make([]string, 0, len(f.vaults))
+		for id := range f.vaults {
+			ids = append(ids, id)
+		}
+		if len(ids) == 0 {
+			return fakeOPError("no vault to store the item in")
+		}
+		sort.Strings(ids)
+		item.Vault = ids[0]
+	}
+	item.UUID = f.id("item")
+	f.items[item.UUID] = item
+	return nil, 0
+}
+
+func (f *fakeOP) document(cmd *fakeOPCommand) ([]byte, int) {
+	if cmd.verb == opPasswordCreate {
+		item := &Item{
+			Template: Category2Template(DocumentCategory),
+			Trashed:  IsNotTrashed,
+			Overview: Overview{Title: cmd.flags["title"]},
+			Details:  Details{DocumentAttributes: &DocumentAttributes{FileName: cmd.flags["file-name"]}},
+		}
+		if tags := cmd.flags["tags"]; tags != "" {
+			item.Overview.Tags = strings.Split(tags, ",")
+		}
+		if out, code := f.placeItem(cmd, item); code != 0 {
+			return out, code
+		}
+		f.documents[item.UUID] = cmd.stdin
+		return fakeOPJSON(map[string]string{"uuid": item.UUID, "vaultUuid": item.Vault})
+	}
+	item, out, code := f.findItem(cmd.arg(0), cmd.flags["vault"])
+	if item == nil {
+		return out, code
+	}
+	switch cmd.verb {
+	case opPasswordGet:
+		return f.documents[item.UUID], 0
+	case opPasswordDelete:
+		delete(f.items, item.UUID)
+		delete(f.documents, item.UUID)
+		return nil, 0
+	}
+	return fakeOPError("unknown document command " + cmd.verb)
+}
+
+func (f *fakeOP) findGroup(id string) *Group {
+	if g, ok := f.groups[id]; ok {
+		return g
+	}
+	for _, g := range f.groups {
+		if g.Name == id {
+			return g
+		}
+	}
+	return nil
+}
+
+func (f *fakeOP) findUser(id string) *User {
+	for _, u := range f.users {
+		if strings.EqualFold(u.UUID, id) || u.Email == id || u.FirstName+" "+u.LastName == id {
+			return u
+		}
+	}
+	return nil
+}
+
+func (f *fakeOP) group(cmd *fakeOPCommand) ([]byte, int) {
+	if cmd.verb == opPasswordCreate {
+		g := &Group{UUID: f.id("group"), Name: cmd.arg(0), State: GroupStateActive}
+		f.groups[g.UUID] = g
+		f.members[g.UUID] = map[string]bool{}
+		return fakeOPJSON(g)
+	}
+	g := f.findGroup(cmd.arg(0))
+	if g == nil {
+		return fakeOPError("The requested resource was not found (404)")
+	}
+	switch cmd.verb {
+	case opPasswordGet:
+		return fakeOPJSON(g)
+	case opPasswordEdit:
+		if name := cmd.flags["name"]; name != "" {
+			g.Name = name
+		}
+		return nil, 0
+	case opPasswordDelete:
+		delete(f.groups, g.UUID)
+		delete(f.members, g.UUID)
+		return nil, 0
+	}
+	return fakeOPError("unknown group command " + cmd.verb)
+}
+
+// user answers get user, list users --group, add user and remove user
+func (f *fakeOP) user(cmd *fakeOPCommand) ([]byte, int) {
+	if cmd.verb == opPasswordList {
+		g := f.findGroup(cmd.flags["group"])
+		if g == nil {
+			return fakeOPError("The requested resource was not found (404)")
+		}
+		users := []*User{}
+		for id := range f.members[g.UUID] {
+			users = append(users, f.users[id])
+		}
+		sort.Slice(users, func(i, j int) bool { return users[i].UUID < users[j].UUID })
+		return fakeOPJSON(users)
+	}
+	u := f.findUser(cmd.arg(0))
+	if u == nil {
+		return fakeOPError("The requested resource was not found (404)")
+	}
+	if cmd.verb == opPasswordGet {
+		return fakeOPJSON(u)
+	}
+	g := f.findGroup(cmd.arg(1))
+	if g == nil {
+		return fakeOPError("The requested resource was not found (404)")
+	}
+	switch cmd.verb {
+	case opPasswordAdd:
+		f.members[g.UUID][u.UUID] = true
+		return nil, 0
+	case opPasswordRemove:
+		delete(f.members[g.UUID], u.UUID)
+		return nil, 0
+	}
+	return fakeOPError("unknown user command " + cmd.verb)
+}
+
+// member answers the v2 group user list, grant and revoke commands with their v1 counterparts
+func (f *fakeOP) member(cmd *fakeOPCommand) ([]byte, int) {
+	switch cmd.verb {
+	case opPasswordList:
+		return f.user(&fakeOPCommand{verb: opPasswordList, flags: map[string]string{"group": cmd.arg(0)}})
+	case "grant":
+		return f.user(&fakeOPCommand{verb: opPasswordAdd, args: []string{cmd.flags["user"], cmd.flags["group"]}})
+	case "revoke":
+		return f.user(&fakeOPCommand{verb: opPasswordRemove, args: []string{cmd.flags["user"], cmd.flags["group"]}})
+	}
+	return fakeOPError("unknown group user command " + cmd.verb)
+}
+
+// fakeOPItemV2 converts the item to the JSON printed by op v2
+func fakeOPItemV2(item *Item) map[string]interface{} {
+	category := strings.ToUpper(strings.ReplaceAll(string(Template2Category(item.Template)), " ", "_"))
+	fields := []map[string]interface{}{}
+	for _, field := range item.Details.Fields {
+		t := "STRING"
+		if field.Type == FieldPassword {
+			t = "CONCEALED"
+		}
+		fields = append(fields, map[string]interface{}{
+			"id":      field.Name,
+			"type":    t,
+			"purpose": strings.ToUpper(field.Designation),
+			"label":   field.Name,
+			"value":   field.Value,
+		})
+	}
+	if item.Details.Password != "" {
+		fields = append(fields, map[string]interface{}{
+			"id": "password", "type": "CONCEALED", "purpose": "PASSWORD", "label": "password", "value": item.Details.Password,
+		})
+	}
+	if item.Details.Notes != "" {
+		fields = append(fields, map[string]interface{}{
+			"id": "notesPlain", "type": "STRING", "purpose": "NOTES", "label": "notesPlain", "value": item.Details.Notes,
+		})
+	}
+	sections := []map[string]interface{}{}
+	for _, section := range item.Details.Sections {
+		sections = append(sections, map[string]interface{}{"id": section.Name, "label": section.Title})
+		for _, field := range section.Fields {
+			fields = append(fields, map[string]interface{}{
+				"id":      field.N,
+				"type":    strings.ToUpper(fieldKey(field)),
+				"label":   field.Text,
+				"value":   field.Value,
+				"section": map[string]string{"id": section.Name, "label": section.Title},
+			})
+		}
+	}
+	urls := []map[string]interface{}{}
+	for i, u := range item.Overview.URLs {
+		urls = append(urls, map[string]interface{}{"label": u.Label, "href": u.Href, "primary": i == 0})
+	}
+	if len(urls) == 0 && item.Overview.URL != "" {
+		urls = append(urls, map[string]interface{}{"href": item.Overview.URL, "primary": true})
+	}
+	return map[string]interface{}{
+		"id":       item.UUID,
+		"title":    item.Overview.Title,
+		"category": category,
+		"vault":    map[string]string{"id": item.Vault},
+		"tags":     item.Overview.Tags,
+		"urls":     urls,
+		"sections": sections,
+		"fields":   fields,
+		"trashed":  item.Trashed == IsTrashed,
+	}
+}
+
+// fakeOPItemFromV2 fills the item from op item create --category login --title foo username=bar
+// assignment statements, or from a JSON template passed over stdin
+func fakeOPItemFromV2(cmd *fakeOPCommand, item *Item) ([]byte, int) {
+	category := strings.ReplaceAll(cmd.flags["category"], "_", " ")
+	item.Template = Category2Template(UnknownCategory)
+	for _, c := range append(commonCategories, LoginCategory, PasswordCategory, IdentityCategory, DocumentCategory, CreditCardCategory, SecureNoteCategory, SoftwareLicenseCategory) {
+		if strings.EqualFold(string(c), category) {
+			item.Template = Category2Template(c)
+		}
+	}
+	assignments := cmd.args
+	if len(cmd.stdin) > 0 {
+		template := struct {
+			Fields []struct {
+				Label string
+				Value string
+			}
+		}{}
+		if err := json.Unmarshal(cmd.stdin, &template); err != nil {
+			return fakeOPError("invalid item template: " + err.Error())
+		}
+		for _, field := range template.Fields {
+			assignments = append(assignments, field.Label+"="+field.Value)
+		}
+	}
+	sections := map[string]*Section{}
+	var titles []string
+	for _, a := range assignments {
+		spl := strings.SplitN(a, "=", 2)
+		if len(spl) != 2 {
+			return fakeOPError("invalid assignment " + a)
+		}
+		label, value := spl[0], spl[1]
+		t := TypeString
+		if i := strings.Index(label, "["); i > 0 && strings.HasSuffix(label, "]") {
+			t = SectionFieldType(label[i+1 : len(label)-1])
+			label = label[:i]
+		}
+		switch label {
+		case "username":
+			item.Details.Fields = append(item.Details.Fields, Field{Name: label, Designation: label, Type: FieldText, Value: value})
+			continue
+		case "password":
+			item.Details.Fields = append(item.Details.Fields, Field{Name: label, Designation: label, Type: FieldPassword, Value: value})
+			continue
+		case "notesPlain":
+			item.Details.Notes = value
+			continue
+		}
+		title, name := "", label
+		if spl := strings.SplitN(label, ".", 2); len(spl) == 2 {
+			title, name = spl[0], spl[1]
+		}
+		s, ok := sections[title]
+		if !ok {
+			s = &Section{Name: "Section_" + fieldNumber(title), Title: title}
+			sections[title] = s
+			titles = append(titles, title)
+		}
+		s.Fields = append(s.Fields, SectionField{Type: t, Text: name, Value: value, N: fieldNumber(name)})
+	}
+	for _, title := range titles {
+		item.Details.Sections = append(item.Details.Sections, *sections[title])
+	}
+	return nil, 0
+}
+
+// fakeOPProviderConfig configures the provider to sign in to the fake account with its op binary
+func fakeOPProviderConfig(t *testing.T, f *fakeOP) string {
+	return fmt.Sprintf(`
+provider "onepassword" {
+  op_binary_path = %q
+  config_dir     = %q
+  subdomain      = "my"
+  email          = "user@example.com"
+  password       = %q
+  secret_key     = "A3-XXXXXX-XXXXXX-XXXXX-XXXXX-XXXXX-XXXXX"
+}
+`, f.binary(t), t.TempDir(), f.Password)
+}
+
+// testAccProviderFactories returns the provider factories for resource.UnitTest,
+// which needs a terraform binary and skips the test without one, or fails it on CI
+func testAccProviderFactories(t *testing.T) map[string]func() (*schema.Provider, error) {
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") == "" {
+		if _, err := exec.LookPath("terraform"); err != nil {
+			// CI installs terraform, a missing binary there must not pass silently
+			if os.Getenv("CI") == "true" {
+				t.Fatal("terraform binary not found on CI, install it or set TF_ACC_TERRAFORM_PATH")
+			}
+			t.Skip("terraform binary not found, set TF_ACC_TERRAFORM_PATH to run the test")
+		}
+	}
+	return map[string]func() (*schema.Provider, error){
+		"onepassword": func() (*schema.Provider, error) {
+			return Provider(), nil
+		},
+	}
+}
+
+func TestFakeOP_providerConfigure(t *testing.T) {
+	f := newFakeOP(t)
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{name: "signin", password: f.Password},
+		{name: "wrong password", password: "wrong", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Provider()
+			diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+				"op_binary_path": f.binary(t),
+				"config_dir":     t.TempDir(),
+				"subdomain":      "my",
+				"email":          "user@example.com",
+				"password":       tt.password,
+				"secret_key":     "A3-XXXXXX-XXXXXX-XXXXX-XXXXX-XXXXX-XXXXX",
+			}))
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("Configure() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			m := p.Meta().(*Meta)
+			if got := strings.TrimSpace(m.onePassClient.Session); got != "fake-session" {
+				t.Errorf("Session = %q, want fake-session", got)
+			}
+			if got := m.onePassClient.Version.String(); got != f.Version {
+				t.Errorf("Version = %s, want %s", got, f.Version)
+			}
+		})
+	}
+}
+
+func TestFakeOP_client(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeOP(t)
+	o := f.client()
+
+	vault, err := o.CreateVault(ctx, &Vault{Name: "ops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := o.ReadVault(ctx, "ops"); err != nil || got == nil || got.UUID != vault.UUID {
+		t.Fatalf("ReadVault() = %v, %v, want %s", got, err, vault.UUID)
+	}
+
+	item := &Item{
+		Template: Category2Template(LoginCategory),
+		Vault:    vault.UUID,
+		Overview: Overview{Title: "db", Tags: []string{"prod"}},
+		Details: Details{
+			Fields: []Field{{Name: "username", Designation: "username", Type: FieldText, Value: "admin"}},
+			Sections: []Section{{Name: "Section_1", Title: "extra", Fields: []SectionField{
+				{Type: TypeString, Text: "host", Value: "db.example.com", N: "host"},
+			}}},
+		},
+	}
+	if err := o.CreateItem(ctx, item); err != nil {
+		t.Fatal(err)
+	}
+	got, err := o.ReadItem(ctx, "db", vault.UUID)
+	if err != nil || got == nil {
+		t.Fatalf("ReadItem() = %v, %v", got, err)
+	}
+	if got.UUID != item.UUID || !reflect.DeepEqual(got.Details, item.Details) || !reflect.DeepEqual(got.Overview.Tags, item.Overview.Tags) {
+		t.Errorf("ReadItem() = %+v, want %+v", got, item)
+	}
+
+	if err := o.ArchiveItem(ctx, item.UUID); err != nil {
+		t.Fatal(err)
+	}
+	if items, err := o.ListItems(ctx, vault.UUID); err != nil || len(items) != 0 {
+		t.Errorf("ListItems() = %v, %v, want no items after archiving", items, err)
+	}
+	if got, err := o.ReadItem(ctx, item.UUID, vault.UUID); err != nil || got == nil || got.Trashed != IsTrashed {
+		t.Errorf("ReadItem() = %v, %v, want the archived item", got, err)
+	}
+
+	doc := &Item{
+		Vault:    vault.UUID,
+		Overview: Overview{Title: "cert"},
+		Details:  Details{DocumentAttributes: &DocumentAttributes{FileName: "cert.pem"}},
+	}
+	if err := o.CreateDocument(ctx, doc, []byte("-----BEGIN CERTIFICATE-----")); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := o.ReadDocument(ctx, doc.UUID); err != nil || string(content) != "-----BEGIN CERTIFICATE-----" {
+		t.Errorf("ReadDocument() = %q, %v", content, err)
+	}
+
+	user := f.AddUser("jane@example.com", "Jane", "Doe")
+	group, err := o.CreateGroup(ctx, &Group{Name: "ops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.CreateGroupMember(ctx, group.UUID, user.UUID); err != nil {
+		t.Fatal(err)
+	}
+	if members, err := o.ListGroupMembers(ctx, group.UUID); err != nil || len(members) != 1 || members[0].Email != user.Email {
+		t.Errorf("ListGroupMembers() = %v, %v, want %s", members, err, user.Email)
+	}
+	if err := o.DeleteGroupMember(ctx, group.UUID, user.UUID); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.DeleteGroup(ctx, group.UUID); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := o.ReadGroup(ctx, group.UUID); err != nil || got != nil {
+		t.Errorf("ReadGroup() = %v, %v, want nil after delete", got, err)
+	}
+
+	if err := o.DeleteVault(ctx, vault.UUID); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := o.ReadItem(ctx, item.UUID, ""); err != nil || got != nil {
+		t.Errorf("ReadItem() = %v, %v, want nil after deleting the vault", got, err)
+	}
+}
+
+func TestFakeOP_v2(t *testing.T) {
+	f := newFakeOP(t)
+	f.session = "fake-session"
+	vault := f.AddVault("ops")
+	user := f.AddUser("jane@example.com", "Jane", "Doe")
+	run := func(args ...string) map[string]interface{} {
+		out, code := f.run(&fakeOPRequest{Args: args, Session: "fake-session"})
+		if code != 0 {
+			t.Fatalf("op %s failed: %s", strings.Join(args, " "), out)
+		}
+		res := map[string]interface{}{}
+		if len(out) > 0 && out[0] == '{' {
+			if err := json.Unmarshal(out, &res); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return res
+	}
+
+	created := run("item", "create", "--category", "login", "--title", "db", "--vault", "ops", "username=admin", "extra.host[url]=https://db.example.com")
+	item := f.Item(created["uuid"].(string))
+	if item == nil || item.Vault != vault.UUID || item.Template != Category2Template(LoginCategory) {
+		t.Fatalf("item create stored %+v", item)
+	}
+	got := run("item", "get", "db", "--vault=ops")
+	if got["id"] != item.UUID || got["category"] != "LOGIN" || len(got["fields"].([]interface{})) != 2 {
+		t.Errorf("item get = %v", got)
+	}
+
+	group := run("group", "create", "ops")
+	run("group", "user", "grant", "--group", "ops", "--user", user.Email)
+	if members := f.members[group["UUID"].(string)]; !members[user.UUID] {
+		t.Errorf("group user grant didn't add %s", user.UUID)
+	}
+	run("group", "user", "revoke", "--group", "ops", "--user", user.Email)
+	if members := f.members[group["UUID"].(string)]; members[user.UUID] {
+		t.Errorf("group user revoke didn't remove %s", user.UUID)
+	}
+
+	if out, code := f.run(&fakeOPRequest{Args: []string{"item", "get", "db"}}); code == 0 || !strings.Contains(string(out), "not currently signed in") {
+		t.Errorf("item get without session = %s, %d", out, code)
+	}
+}
+
+func TestAccFakeOP_vault(t *testing.T) {
+	f := newFakeOP(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(t),
+		Steps: []resource.TestStep{
+			{
+				Config: fakeOPProviderConfig(t, f) + `
+resource "onepassword_vault" "ops" {
+  name = "ops"
+}
+`,
+				Check: resource.TestCheckResourceAttr("onepassword_vault.ops", "name", "ops"),
+			},
+		},
+	})
+}
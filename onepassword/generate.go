@@ -0,0 +1,282 @@
+package onepassword
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// GenerateConfig writes import blocks and resource definitions for every item of the vault.
+// Items are read with the read function of the matching resource type, sensitive values
+// are replaced by references to variables which are declared at the end of the output.
+func GenerateConfig(ctx context.Context, w io.Writer, meta interface{}, vaultID string) error {
+	m := meta.(*Meta)
+	vault, err := m.onePassClient.ReadVault(ctx, vaultID)
+	if err != nil {
+		return err
+	}
+	if vault == nil {
+		return fmt.Errorf("vault %s not found", vaultID)
+	}
+	items, err := m.onePassClient.ListItems(ctx, vault.UUID)
+	if err != nil {
+		return err
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Overview.Title == items[j].Overview.Title {
+			return items[i].UUID < items[j].UUID
+		}
+		return items[i].Overview.Title < items[j].Overview.Title
+	})
+
+	g := &configGenerator{
+		file:  hclwrite.NewEmptyFile(),
+		names: map[string]bool{},
+	}
+	resources := Provider().ResourcesMap
+	for _, item := range items {
+		c := Template2Category(item.Template)
+		if c == UnknownCategory {
+			g.file.Body().AppendUnstructuredTokens(comment(fmt.Sprintf("item %s (%s) skipped, template %s is not supported", item.Overview.Title, item.UUID, item.Template)))
+			continue
+		}
+		resourceType := categoryResource(c)
+		r := resources[resourceType]
+		d := r.Data(nil)
+		d.SetId(item.UUID)
+		if err := d.Set("vault", vault.UUID); err != nil {
+			return err
+		}
+		if diags := r.ReadContext(ctx, d, meta); diags.HasError() {
+			return fmt.Errorf("could not read item %s: %s", item.UUID, diags[0].Summary)
+		}
+		if d.Id() == "" {
+			continue
+		}
+		g.resource(resourceType, r, d, item)
+	}
+	g.variables()
+
+	_, err = w.Write(g.file.Bytes())
+	return err
+}
+
+type configGenerator struct {
+	file    *hclwrite.File
+	names   map[string]bool
+	secrets []string
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// uniqueName turns the title into a Terraform identifier which is not used yet
+func (g *configGenerator) uniqueName(title string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(title), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "item_" + name
+	}
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+func (g *configGenerator) resource(resourceType string, r *schema.Resource, d *schema.ResourceData, item Item) {
+	name := g.uniqueName(item.Overview.Title)
+	body := g.file.Body()
+
+	imp := body.AppendNewBlock("import", nil).Body()
+	imp.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: name},
+	})
+	imp.SetAttributeValue("id", cty.StringVal(item.UUID))
+	body.AppendNewline()
+
+	values := map[string]interface{}{}
+	for k := range r.Schema {
+		values[k] = d.Get(k)
+	}
+	g.body(body.AppendNewBlock("resource", []string{resourceType, name}).Body(), r.Schema, values, name)
+	body.AppendNewline()
+}
+
+// body writes configurable attributes with non-empty values and nested blocks
+func (g *configGenerator) body(body *hclwrite.Body, s map[string]*schema.Schema, values map[string]interface{}, prefix string) {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	// attributes go first and nested blocks after them, as terraform fmt lays them out
+	sort.Slice(keys, func(i, j int) bool {
+		bi, bj := isBlock(s[keys[i]]), isBlock(s[keys[j]])
+		if bi != bj {
+			return bj
+		}
+		return keys[i] < keys[j]
+	})
+
+	written := map[string]bool{}
+	for _, k := range keys {
+		sch := s[k]
+		v := values[k]
+		if !sch.Required && !sch.Optional {
+			continue
+		}
+		if !sch.Required && isEmptyValue(v) {
+			continue
+		}
+		if skipConflicting(sch, values, written) {
+			continue
+		}
+		written[k] = true
+
+		if elem, ok := sch.Elem.(*schema.Resource); ok {
+			for i, e := range listValue(v) {
+				label := k
+				if name, ok := e["name"].(string); ok && name != "" {
+					label = name
+				} else if i > 0 {
+					label = k + "_" + strconv.Itoa(i+1)
+				}
+				g.body(body.AppendNewBlock(k, nil).Body(), elem.Schema, e, prefix+"_"+label)
+			}
+			continue
+		}
+		if sch.Sensitive {
+			body.SetAttributeTraversal(k, hcl.Traversal{
+				hcl.TraverseRoot{Name: "var"},
+				hcl.TraverseAttr{Name: g.secret(prefix + "_" + k)},
+			})
+			continue
+		}
+		body.SetAttributeValue(k, ctyValue(v))
+	}
+}
+
+func isBlock(sch *schema.Schema) bool {
+	_, ok := sch.Elem.(*schema.Resource)
+	return ok
+}
+
+// skipConflicting keeps a single attribute out of a ConflictsWith group,
+// a list with several values wins over the attributes it conflicts with, otherwise the first one is written
+func skipConflicting(sch *schema.Schema, values map[string]interface{}, written map[string]bool) bool {
+	for _, other := range sch.ConflictsWith {
+		if written[other] {
+			return true
+		}
+		if len(listValue(values[other])) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *configGenerator) secret(name string) string {
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	unique := name
+	for i := 2; g.names["var."+unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	g.names["var."+unique] = true
+	g.secrets = append(g.secrets, unique)
+	return unique
+}
+
+// variables declares the variables holding sensitive values
+func (g *configGenerator) variables() {
+	body := g.file.Body()
+	for _, name := range g.secrets {
+		v := body.AppendNewBlock("variable", []string{name}).Body()
+		v.SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+		v.SetAttributeValue("sensitive", cty.True)
+		body.AppendNewline()
+	}
+}
+
+func comment(text string) hclwrite.Tokens {
+	return hclwrite.Tokens{{Type: hclsyntax.TokenComment, Bytes: []byte("# " + text + "\n\n")}}
+}
+
+func isEmptyValue(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case bool:
+		return !val
+	case int:
+		return val == 0
+	case float64:
+		return val == 0
+	case []interface{}:
+		return len(val) == 0
+	case *schema.Set:
+		return val.Len() == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	}
+	return false
+}
+
+// listValue returns the elements of a list or set value
+func listValue(v interface{}) []map[string]interface{} {
+	var list []interface{}
+	switch val := v.(type) {
+	case []interface{}:
+		list = val
+	case *schema.Set:
+		list = val.List()
+	}
+	res := make([]map[string]interface{}, 0, len(list))
+	for _, e := range list {
+		m, _ := e.(map[string]interface{})
+		res = append(res, m)
+	}
+	return res
+}
+
+func ctyValue(v interface{}) cty.Value {
+	switch val := v.(type) {
+	case string:
+		return cty.StringVal(val)
+	case bool:
+		return cty.BoolVal(val)
+	case int:
+		return cty.NumberIntVal(int64(val))
+	case float64:
+		return cty.NumberFloatVal(val)
+	case *schema.Set:
+		return ctyValue(val.List())
+	case []interface{}:
+		if len(val) == 0 {
+			return cty.ListValEmpty(cty.String)
+		}
+		vals := make([]cty.Value, 0, len(val))
+		for _, e := range val {
+			vals = append(vals, ctyValue(e))
+		}
+		return cty.TupleVal(vals)
+	case map[string]interface{}:
+		vals := make(map[string]cty.Value, len(val))
+		for k, e := range val {
+			vals[k] = ctyValue(e)
+		}
+		return cty.ObjectVal(vals)
+	}
+	return cty.NullVal(cty.DynamicPseudoType)
+}
@@ -0,0 +1,73 @@
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGenerateConfig(t *testing.T) {
+	config := &mockOnePassConfig{}
+	config.runCmd = func() (string, error) {
+		out, err := generateTestOutput(config.execCommandResults)
+		// the mock echoes the output through sh, which doesn't allow line breaks
+		return strings.Join(strings.Fields(out), " "), err
+	}
+	m := &Meta{onePassClient: mockOnePassClient(config)}
+
+	out := &bytes.Buffer{}
+	if err := GenerateConfig(context.Background(), out, m, "ops"); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"import {\n  to = onepassword_item_login.db_admin\n  id = \"login1\"\n}",
+		"resource \"onepassword_item_login\" \"db_admin\" {",
+		"password = var.db_admin_password",
+		"username = \"admin\"",
+		"url      = \"https://db.example.com\"",
+		"concealed = var.db_admin_extra_pin_concealed",
+		"string = \"db\"",
+		"resource \"onepassword_item_secure_note\" \"readme\" {",
+		"variable \"db_admin_password\" {\n  type      = string\n  sensitive = true\n}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("GenerateConfig() output doesn't contain %q:\n%s", want, got)
+		}
+	}
+	for _, secret := range []string{"hunter2", "1234"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("GenerateConfig() output contains secret %s:\n%s", secret, got)
+		}
+	}
+}
+
+func generateTestOutput(args []string) (string, error) {
+	switch {
+	case args[2] == VaultResource:
+		return `{ "uuid": "vault1", "name": "ops" }`, nil
+	case args[1] == opPasswordList:
+		return `[
+				{ "uuid": "login1", "templateUUID": "001", "vaultUUID": "vault1", "overview": { "title": "DB Admin" } },
+				{ "uuid": "note1", "templateUUID": "003", "vaultUUID": "vault1", "overview": { "title": "readme" } }
+			]`, nil
+	case args[3] == "login1":
+		return `{ "uuid": "login1", "templateUUID": "001", "vaultUUID": "vault1", "trashed": "N",
+				"overview": { "title": "DB Admin", "url": "https://db.example.com", "tags": ["prod"] },
+				"details": {
+					"fields": [
+						{ "name": "username", "value": "admin" },
+						{ "name": "password", "value": "hunter2" }
+					],
+					"sections": [
+						{ "name": "Section_1", "title": "extra", "fields": [
+							{ "k": "concealed", "t": "pin", "v": "1234", "n": "F1" },
+							{ "k": "string", "t": "host", "v": "db", "n": "F2" }
+						] }
+					]
+				} }`, nil
+	}
+	return `{ "uuid": "note1", "templateUUID": "003", "vaultUUID": "vault1", "trashed": "N",
+			"overview": { "title": "readme" }, "details": { "notesPlain": "hello" } }`, nil
+}
@@ -1,7 +1,9 @@
 package onepassword
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -23,13 +25,17 @@ type Group struct {
 	State string
 }
 
-// ReadGroup gets an existing 1Password Group
-func (o *OnePassClient) ReadGroup(id string) (*Group, error) {
+// ReadGroup gets an existing 1Password Group, nil is returned when the Group doesn't exist
+func (o *OnePassClient) ReadGroup(ctx context.Context, id string) (*Group, error) {
 	group := &Group{}
 	args := []string{opPasswordGet, GroupResource, id}
-	res, err := o.RunSimpleCmd(args...)
+	res, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
-		return nil, prettyError(args, res, err)
+		err = prettyError(args, res, err)
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
 	}
 	if err = json.Unmarshal(res, group); err != nil {
 		return nil, err
@@ -38,14 +44,14 @@ func (o *OnePassClient) ReadGroup(id string) (*Group, error) {
 }
 
 // ListGroupMembers lists the existing Users in a given Group
-func (o *OnePassClient) ListGroupMembers(id string) ([]User, error) {
+func (o *OnePassClient) ListGroupMembers(ctx context.Context, id string) ([]User, error) {
 	users := []User{}
 	if id == "" {
 		return users, fmt.Errorf("Must provide an identifier to list group members")
 	}
 
 	args := []string{opPasswordList, "users", "--" + GroupResource, id}
-	res, err := o.RunSimpleCmd(args...)
+	res, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
 		return nil, prettyError(args, res, err)
 	}
@@ -56,9 +62,9 @@ func (o *OnePassClient) ListGroupMembers(id string) ([]User, error) {
 }
 
 // CreateGroup creates a new 1Password Group
-func (o *OnePassClient) CreateGroup(v *Group) (*Group, error) {
+func (o *OnePassClient) CreateGroup(ctx context.Context, v *Group) (*Group, error) {
 	args := []string{opPasswordCreate, GroupResource, v.Name}
-	res, err := o.RunSimpleCmd(args...)
+	res, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
 		return nil, prettyError(args, res, err)
 	}
@@ -69,9 +75,9 @@ func (o *OnePassClient) CreateGroup(v *Group) (*Group, error) {
 }
 
 // CreateGroupMember adds a User to a Group
-func (o *OnePassClient) CreateGroupMember(groupID string, userID string) error {
+func (o *OnePassClient) CreateGroupMember(ctx context.Context, groupID string, userID string) error {
 	args := []string{opPasswordAdd, UserResource, userID, groupID}
-	res, err := o.RunSimpleCmd(args...)
+	res, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
 		return prettyError(args, res, err)
 	}
@@ -79,9 +85,9 @@ func (o *OnePassClient) CreateGroupMember(groupID string, userID string) error {
 }
 
 // UpdateGroup updates an existing 1Password Group
-func (o *OnePassClient) UpdateGroup(id string, v *Group) error {
+func (o *OnePassClient) UpdateGroup(ctx context.Context, id string, v *Group) error {
 	args := []string{opPasswordEdit, GroupResource, id, "--name=" + v.Name}
-	res, err := o.RunSimpleCmd(args...)
+	res, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
 		return prettyError(args, res, err)
 	}
@@ -89,14 +95,14 @@ func (o *OnePassClient) UpdateGroup(id string, v *Group) error {
 }
 
 // DeleteGroup deletes a 1Password Group
-func (o *OnePassClient) DeleteGroup(id string) error {
-	return o.Delete(GroupResource, id)
+func (o *OnePassClient) DeleteGroup(ctx context.Context, id string) error {
+	return o.Delete(ctx, GroupResource, id)
 }
 
 // DeleteGroupMember removes a User from a Group
-func (o *OnePassClient) DeleteGroupMember(groupID string, userID string) error {
+func (o *OnePassClient) DeleteGroupMember(ctx context.Context, groupID string, userID string) error {
 	args := []string{opPasswordRemove, UserResource, userID, groupID}
-	res, err := o.RunSimpleCmd(args...)
+	res, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
 		return prettyError(args, res, err)
 	}
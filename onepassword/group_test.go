@@ -1,6 +1,7 @@
 package onepassword
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
@@ -29,7 +30,7 @@ func TestOnePassClient_ReadGroup(t *testing.T) {
 				},
 			},
 			args:            args{id: "uniq"},
-			wantExecResults: []string{"op", "get", "group", "uniq", "--session="},
+			wantExecResults: []string{"op", "get", "group", "uniq"},
 			want:            &Group{UUID: "uniq", Name: "foo"},
 		},
 		{
@@ -40,7 +41,7 @@ func TestOnePassClient_ReadGroup(t *testing.T) {
 				},
 			},
 			args:            args{id: "uniq"},
-			wantExecResults: []string{"op", "get", "group", "uniq", "--session="},
+			wantExecResults: []string{"op", "get", "group", "uniq"},
 			wantErr:         true,
 		},
 		{
@@ -51,9 +52,19 @@ func TestOnePassClient_ReadGroup(t *testing.T) {
 				},
 			},
 			args:            args{id: "uniq"},
-			wantExecResults: []string{"op", "get", "group", "uniq", "--session="},
+			wantExecResults: []string{"op", "get", "group", "uniq"},
 			wantErr:         true,
 		},
+		{
+			name: "not found",
+			fields: fields{
+				runCmd: func() (string, error) {
+					return ``, fmt.Errorf("The requested resource was not found")
+				},
+			},
+			args:            args{id: "uniq"},
+			wantExecResults: []string{"op", "get", "group", "uniq"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -62,7 +73,7 @@ func TestOnePassClient_ReadGroup(t *testing.T) {
 			}
 			o := mockOnePassClient(config)
 
-			got, err := o.ReadGroup(tt.args.id)
+			got, err := o.ReadGroup(context.Background(), tt.args.id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("OnePassClient.ReadGroup() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -100,7 +111,7 @@ func TestOnePassClient_CreateGroup(t *testing.T) {
 				},
 			},
 			args:            args{v: &Group{Name: "foo"}},
-			wantExecResults: []string{"op", "create", "group", "foo", "--session="},
+			wantExecResults: []string{"op", "create", "group", "foo"},
 			want:            &Group{UUID: "uniq", Name: "foo"},
 		},
 		{
@@ -111,7 +122,7 @@ func TestOnePassClient_CreateGroup(t *testing.T) {
 				},
 			},
 			args:            args{v: &Group{Name: "foo"}},
-			wantExecResults: []string{"op", "create", "group", "foo", "--session="},
+			wantExecResults: []string{"op", "create", "group", "foo"},
 			wantErr:         true,
 		},
 		{
@@ -122,7 +133,7 @@ func TestOnePassClient_CreateGroup(t *testing.T) {
 				},
 			},
 			args:            args{v: &Group{Name: "foo"}},
-			wantExecResults: []string{"op", "create", "group", "foo", "--session="},
+			wantExecResults: []string{"op", "create", "group", "foo"},
 			wantErr:         true,
 		},
 	}
@@ -133,7 +144,7 @@ func TestOnePassClient_CreateGroup(t *testing.T) {
 			}
 			o := mockOnePassClient(config)
 
-			got, err := o.CreateGroup(tt.args.v)
+			got, err := o.CreateGroup(context.Background(), tt.args.v)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("OnePassClient.CreateGroup() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -175,7 +186,7 @@ func TestOnePassClient_UpdateGroup(t *testing.T) {
 				id: "uniq",
 				v:  &Group{Name: "foo"},
 			},
-			wantExecResults: []string{"op", "edit", "group", "uniq", "--name=foo", "--session="},
+			wantExecResults: []string{"op", "edit", "group", "uniq", "--name=foo"},
 			want:            &Group{UUID: "uniq", Name: "foo"},
 		},
 		{
@@ -189,7 +200,7 @@ func TestOnePassClient_UpdateGroup(t *testing.T) {
 				id: "uniq",
 				v:  &Group{Name: "foo"},
 			},
-			wantExecResults: []string{"op", "edit", "group", "uniq", "--name=foo", "--session="},
+			wantExecResults: []string{"op", "edit", "group", "uniq", "--name=foo"},
 			wantErr:         true,
 		},
 	}
@@ -200,7 +211,7 @@ func TestOnePassClient_UpdateGroup(t *testing.T) {
 			}
 			o := mockOnePassClient(config)
 
-			err := o.UpdateGroup(tt.args.id, tt.args.v)
+			err := o.UpdateGroup(context.Background(), tt.args.id, tt.args.v)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("OnePassClient.UpdateGroup() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -235,7 +246,7 @@ func TestOnePassClient_DeleteGroup(t *testing.T) {
 				},
 			},
 			args:            args{id: "uniq"},
-			wantExecResults: []string{"op", "delete", "group", "uniq", "--session="},
+			wantExecResults: []string{"op", "delete", "group", "uniq"},
 			want:            &Group{UUID: "uniq", Name: "foo"},
 		},
 		{
@@ -246,7 +257,7 @@ func TestOnePassClient_DeleteGroup(t *testing.T) {
 				},
 			},
 			args:            args{id: "uniq"},
-			wantExecResults: []string{"op", "delete", "group", "uniq", "--session="},
+			wantExecResults: []string{"op", "delete", "group", "uniq"},
 			wantErr:         true,
 		},
 	}
@@ -257,7 +268,7 @@ func TestOnePassClient_DeleteGroup(t *testing.T) {
 			}
 			o := mockOnePassClient(config)
 
-			err := o.DeleteGroup(tt.args.id)
+			err := o.DeleteGroup(context.Background(), tt.args.id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("OnePassClient.DeleteGroup() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -292,7 +303,7 @@ func TestOnePassClient_ListGroupMembers(t *testing.T) {
 				},
 			},
 			args:            args{id: "uniq"},
-			wantExecResults: []string{"op", "list", "users", "--group", "uniq", "--session="},
+			wantExecResults: []string{"op", "list", "users", "--group", "uniq"},
 			want:            []User{{UUID: "uniq", FirstName: "Testy", LastName: "Testerton"}},
 		},
 		{
@@ -303,7 +314,7 @@ func TestOnePassClient_ListGroupMembers(t *testing.T) {
 				},
 			},
 			args:            args{id: "uniq"},
-			wantExecResults: []string{"op", "list", "users", "--group", "uniq", "--session="},
+			wantExecResults: []string{"op", "list", "users", "--group", "uniq"},
 			wantErr:         true,
 		},
 		{
@@ -320,7 +331,7 @@ func TestOnePassClient_ListGroupMembers(t *testing.T) {
 			}
 			o := mockOnePassClient(config)
 
-			got, err := o.ListGroupMembers(tt.args.id)
+			got, err := o.ListGroupMembers(context.Background(), tt.args.id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("OnePassClient.ListGroupMembers() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -358,7 +369,7 @@ func TestOnePassClient_CreateGroupMember(t *testing.T) {
 				},
 			},
 			args:            args{userID: "userName", groupID: "groupName"},
-			wantExecResults: []string{"op", "add", "user", "groupName", "userName", "--session="},
+			wantExecResults: []string{"op", "add", "user", "groupName", "userName"},
 		},
 		{
 			name: "error",
@@ -368,7 +379,7 @@ func TestOnePassClient_CreateGroupMember(t *testing.T) {
 				},
 			},
 			args:            args{userID: "userName", groupID: "groupName"},
-			wantExecResults: []string{"op", "add", "user", "groupName", "userName", "--session="},
+			wantExecResults: []string{"op", "add", "user", "groupName", "userName"},
 			wantErr:         true,
 		},
 	}
@@ -379,7 +390,7 @@ func TestOnePassClient_CreateGroupMember(t *testing.T) {
 			}
 			o := mockOnePassClient(config)
 
-			err := o.CreateGroupMember(tt.args.userID, tt.args.groupID)
+			err := o.CreateGroupMember(context.Background(), tt.args.userID, tt.args.groupID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("OnePassClient.ListGroupMembers() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -414,7 +425,7 @@ func TestOnePassClient_DeleteGroupMember(t *testing.T) {
 				},
 			},
 			args:            args{userID: "userName", groupID: "groupName"},
-			wantExecResults: []string{"op", "remove", "user", "groupName", "userName", "--session="},
+			wantExecResults: []string{"op", "remove", "user", "groupName", "userName"},
 		},
 		{
 			name: "error",
@@ -424,7 +435,7 @@ func TestOnePassClient_DeleteGroupMember(t *testing.T) {
 				},
 			},
 			args:            args{userID: "userName", groupID: "groupName"},
-			wantExecResults: []string{"op", "remove", "user", "groupName", "userName", "--session="},
+			wantExecResults: []string{"op", "remove", "user", "groupName", "userName"},
 			wantErr:         true,
 		},
 	}
@@ -435,7 +446,7 @@ func TestOnePassClient_DeleteGroupMember(t *testing.T) {
 			}
 			o := mockOnePassClient(config)
 
-			err := o.DeleteGroupMember(tt.args.userID, tt.args.groupID)
+			err := o.DeleteGroupMember(context.Background(), tt.args.userID, tt.args.groupID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("OnePassClient.ListGroupMembers() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1,12 +1,14 @@
 package onepassword
 
 import (
-	"crypto/rand"
+	"crypto/sha256"
 	"fmt"
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/Masterminds/semver"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -69,6 +71,42 @@ func urlValidateDiag() schema.SchemaValidateDiagFunc {
 	}
 }
 
+func durationValidateDiag() schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		diags := stringDiag()(v, path)
+		val, _ := v.(string)
+		if len(diags) == 0 {
+			if _, err := time.ParseDuration(val); err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Value is not duration",
+					Detail:        fmt.Sprintf("%s is not a duration, use values like 30s or 5m", val),
+					AttributePath: path,
+				})
+			}
+		}
+		return diags
+	}
+}
+
+func semverValidateDiag() schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		diags := stringDiag()(v, path)
+		val, _ := v.(string)
+		if len(diags) == 0 && val != "" {
+			if _, err := semver.NewVersion(val); err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Value is not version",
+					Detail:        fmt.Sprintf("%s is not a semantic version", val),
+					AttributePath: path,
+				})
+			}
+		}
+		return diags
+	}
+}
+
 func stringInSliceDiag(ss []string, empty bool) schema.SchemaValidateDiagFunc {
 	return func(v interface{}, path cty.Path) diag.Diagnostics {
 		diags := stringDiag()(v, path)
@@ -87,6 +125,74 @@ func stringInSliceDiag(ss []string, empty bool) schema.SchemaValidateDiagFunc {
 	}
 }
 
+func intBetweenDiag(min, max int) schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		var diags diag.Diagnostics
+		val, ok := v.(int)
+		if !ok || val < min || val > max {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Value is out of range",
+				Detail:        fmt.Sprintf("%v is not between %d and %d", v, min, max),
+				AttributePath: path,
+			})
+		}
+		return diags
+	}
+}
+
+func dateValidateDiag() schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		diags := stringDiag()(v, path)
+		val, _ := v.(string)
+		if len(diags) == 0 && val != "" {
+			if _, err := ParseDate(val); err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Value is not date",
+					Detail:        err.Error(),
+					AttributePath: path,
+				})
+			}
+		}
+		return diags
+	}
+}
+
+func monthYearValidateDiag() schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		diags := stringDiag()(v, path)
+		val, _ := v.(string)
+		if len(diags) == 0 && val != "" {
+			if _, err := ParseMonthYear(val); err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Value is not month",
+					Detail:        err.Error(),
+					AttributePath: path,
+				})
+			}
+		}
+		return diags
+	}
+}
+
+func cardNumberValidateDiag() schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		diags := stringDiag()(v, path)
+		val, _ := v.(string)
+		if len(diags) == 0 && val != "" && !luhnValid(cardNumberDigits(val)) {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Value is not card number",
+				Detail:        "card number has to be 12 to 19 digits with a valid Luhn check digit, spaces and dashes are allowed",
+				AttributePath: path,
+			})
+		}
+		return diags
+	}
+}
+
 func stringInSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
@@ -96,10 +202,20 @@ func stringInSlice(a string, list []string) bool {
 	return false
 }
 
-func fieldNumber() (string, error) {
-	b := make([]byte, 16)
-	if _, err := rand.Read(b); err != nil {
-		return "", err
+// fieldNumber derives a stable identifier from the section or field name,
+// so the same configuration always produces the same item.
+func fieldNumber(name string) string {
+	b := sha256.Sum256([]byte(name))
+	return strings.ToUpper(fmt.Sprintf("%x", b[:16]))
+}
+
+// uniqueFieldNumber works like fieldNumber, but keeps track of identifiers
+// already used in the same scope and adds a numeric suffix to the name on collision.
+func uniqueFieldNumber(name string, used map[string]bool) string {
+	id := fieldNumber(name)
+	for i := 2; used[id]; i++ {
+		id = fieldNumber(fmt.Sprintf("%s#%d", name, i))
 	}
-	return strings.ToUpper(fmt.Sprintf("%x", b)), nil
+	used[id] = true
+	return id
 }
@@ -0,0 +1,342 @@
+package onepassword
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+// defaultOPVersion is the minimum op version unless min_op_version is set
+const defaultOPVersion = "1.4.0"
+
+// opDownloadURL is the location of op release archives, formatted with version, OS and architecture
+var opDownloadURL = "https://cache.agilebits.com/dist/1P/op/pkg/v%[1]s/op_%[2]s_%[3]s_v%[1]s.zip"
+
+// opChecksums pins SHA256 checksums of op release archives by "version/os_arch".
+// Add the checksums of the archives published by AgileBits here when bumping the default version,
+// e.g. "1.4.0/linux_amd64" from curl -sL <opDownloadURL> | sha256sum. Archives without
+// a pinned or configured checksum are never installed.
+var opChecksums = map[string]string{}
+
+// opInstallPlatforms are the "os_arch" pairs op is installed automatically on,
+// opChecksums must pin the default version for each of them
+var opInstallPlatforms = []string{"linux_amd64", "linux_386", "linux_arm", "linux_arm64"}
+
+// opChecksumKey is the opChecksums key of the archive
+func opChecksumKey(version, goos, goarch string) string {
+	return fmt.Sprintf("%s/%s_%s", version, goos, goarch)
+}
+
+// opVersionPolicy is the range of op versions accepted by the provider
+type opVersionPolicy struct {
+	min         string
+	max         string
+	constraints *semver.Constraints
+}
+
+func newOPVersionPolicy(min, max string) (*opVersionPolicy, error) {
+	c := ">= " + min
+	if max != "" {
+		c += ", <= " + max
+	}
+	constraints, err := semver.NewConstraint(c)
+	if err != nil {
+		return nil, err
+	}
+	return &opVersionPolicy{min: min, max: max, constraints: constraints}, nil
+}
+
+func (p *opVersionPolicy) Check(v *semver.Version) bool {
+	return p.constraints.Check(v)
+}
+
+func (p *opVersionPolicy) String() string {
+	if p.max != "" {
+		return fmt.Sprintf(">= %s, <= %s", p.min, p.max)
+	}
+	return ">= " + p.min
+}
+
+// opClient resolves the op binary from the provider configuration:
+// op_binary_path is used as is, otherwise op_path and PATH are searched,
+// and the minimum accepted version is downloaded when nothing suitable is found.
+func (m *Meta) opClient(ctx context.Context) (string, *semver.Version, error) {
+	policy, err := newOPVersionPolicy(m.data.Get("min_op_version").(string), m.data.Get("max_op_version").(string))
+	if err != nil {
+		return "", nil, err
+	}
+
+	if bin := m.data.Get("op_binary_path").(string); bin != "" {
+		v, err := opVersion(ctx, bin)
+		if err != nil {
+			return "", nil, err
+		}
+		if !policy.Check(v) {
+			return "", nil, fmt.Errorf("op_binary_path %s has version %s, but %s is required", bin, v, policy)
+		}
+		return bin, v, nil
+	}
+
+	var candidates []string
+	for _, c := range m.data.Get("op_path").([]interface{}) {
+		candidates = append(candidates, c.(string))
+	}
+	bin, v, findErr := findOPClient(ctx, candidates, policy)
+	if findErr == nil {
+		return bin, v, nil
+	}
+
+	v, err = semver.NewVersion(policy.min)
+	if err != nil {
+		return "", nil, err
+	}
+	if !policy.Check(v) {
+		return "", nil, fmt.Errorf("%s\nmin_op_version %s doesn't satisfy %s, nothing to install", findErr, v, policy)
+	}
+	bin, err = installOPClient(ctx, v.String(), m.data.Get("op_checksum").(string))
+	if err != nil {
+		return "", nil, fmt.Errorf("%s\n%s", findErr, err)
+	}
+	return bin, v, nil
+}
+
+// findOPClient returns the first op binary satisfying the version policy.
+// Candidates are binaries or directories containing op, PATH is searched last.
+// The error lists every location checked and why it was rejected.
+func findOPClient(ctx context.Context, candidates []string, policy *opVersionPolicy) (string, *semver.Version, error) {
+	var looked []string
+	for _, candidate := range append(candidates, "op") {
+		bin := candidate
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			bin = filepath.Join(candidate, "op")
+		}
+		path, err := exec.LookPath(bin)
+		if err != nil {
+			if !strings.ContainsRune(bin, os.PathSeparator) {
+				bin += " on PATH"
+			}
+			looked = append(looked, fmt.Sprintf("%s: not found", bin))
+			continue
+		}
+		v, err := opVersion(ctx, path)
+		if err != nil {
+			looked = append(looked, fmt.Sprintf("%s: %s", path, err))
+			continue
+		}
+		if !policy.Check(v) {
+			looked = append(looked, fmt.Sprintf("%s: version %s doesn't satisfy %s", path, v, policy))
+			continue
+		}
+		return path, v, nil
+	}
+	return "", nil, fmt.Errorf("No op client satisfying %s found, looked in:\n  %s", policy, strings.Join(looked, "\n  "))
+}
+
+// opVersion asks the op binary for its version
+func opVersion(ctx context.Context, bin string) (*semver.Version, error) {
+	o, err := exec.CommandContext(ctx, bin, "--version").Output()
+	if err != nil {
+		return nil, fmt.Errorf("Trouble calling: %s\nError: %s\nOutput: %s", bin, err, o)
+	}
+	v, err := semver.NewVersion(strings.TrimSpace(string(o)))
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected version of %s: [%s]", bin, strings.TrimSpace(string(o)))
+	}
+	return v, nil
+}
+
+// installOPClient downloads op into the per-user cache directory.
+// The archive is verified against its checksum before anything is unpacked,
+// and the binary is moved into place atomically, so a partially written or
+// tampered binary is never used. A cached binary is only reused when it was
+// installed from the same archive and still has the checksum recorded then.
+func installOPClient(ctx context.Context, version string, checksum string) (string, error) {
+	if runtime.GOOS == "darwin" {
+		return "", fmt.Errorf("Unable to automatically install v%s of the op client. Please install manually from https://app-updates.agilebits.com/product_history/CLI", version)
+	}
+
+	if checksum == "" {
+		checksum = opChecksums[opChecksumKey(version, runtime.GOOS, runtime.GOARCH)]
+	}
+	if checksum == "" {
+		return "", fmt.Errorf("No checksum is pinned for v%s of the op client on %s_%s. Set op_checksum or install op manually and set op_binary_path", version, runtime.GOOS, runtime.GOARCH)
+	}
+
+	dir, err := opCacheDir(version)
+	if err != nil {
+		return "", err
+	}
+	bin := filepath.Join(dir, "op")
+	if cachedOPClient(bin, checksum) {
+		return bin, nil
+	}
+
+	binZip, err := downloadOPClient(ctx, dir, version, checksum)
+	if binZip != "" {
+		defer os.Remove(binZip)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	tmpDir, err := ioutil.TempDir(dir, "unpack-")
+	if err != nil {
+		return "", fmt.Errorf("Could not create temp dir for op client: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := unzip(binZip, tmpDir); err != nil {
+		return "", fmt.Errorf("Could not unzip op client: %w", err)
+	}
+	binSum, err := fileChecksum(filepath.Join(tmpDir, "op"))
+	if err != nil {
+		return "", fmt.Errorf("Could not hash op client: %w", err)
+	}
+	if err := os.Rename(filepath.Join(tmpDir, "op"), bin); err != nil {
+		return "", fmt.Errorf("Could not install op client: %w", err)
+	}
+	if err := ioutil.WriteFile(bin+".sha256", []byte(checksum+" "+binSum+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("Could not record checksum of op client: %w", err)
+	}
+	return bin, nil
+}
+
+// cachedOPClient reports whether bin was installed from the archive with the given checksum
+// and wasn't modified since. The checksums are recorded next to the binary as
+// "<archive sha256> <binary sha256>".
+func cachedOPClient(bin string, checksum string) bool {
+	recorded, err := ioutil.ReadFile(bin + ".sha256")
+	if err != nil {
+		return false
+	}
+	sums := strings.Fields(string(recorded))
+	if len(sums) != 2 || !strings.EqualFold(sums[0], checksum) {
+		return false
+	}
+	sum, err := fileChecksum(bin)
+	return err == nil && sum == sums[1]
+}
+
+// fileChecksum returns the hex encoded SHA256 checksum of the file
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// opCacheDir returns the cache directory for the given op version.
+// It's created readable by the current user only and refused when others can write to it.
+func opCacheDir(version string) (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("Could not find cache dir for op client: %w", err)
+	}
+	dir := filepath.Join(cache, "terraform-provider-onepassword", version)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("Could not create cache dir for op client: %w", err)
+	}
+	for d := dir; d != cache; d = filepath.Dir(d) {
+		info, err := os.Stat(d)
+		if err != nil {
+			return "", err
+		}
+		if info.Mode().Perm()&0022 != 0 {
+			return "", fmt.Errorf("Cache dir %s for op client is writable by other users", d)
+		}
+	}
+	return dir, nil
+}
+
+// downloadOPClient stores the release archive in dir and verifies its SHA256 checksum.
+// The returned path should be removed by the caller even if an error is returned.
+func downloadOPClient(ctx context.Context, dir string, version string, checksum string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(opDownloadURL, version, runtime.GOOS, runtime.GOARCH), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Could not retrieve zipped op release: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Could not retrieve zipped op release: %s", resp.Status)
+	}
+
+	out, err := ioutil.TempFile(dir, "op-*.zip")
+	if err != nil {
+		return "", fmt.Errorf("Could not create temp file for op client: %w", err)
+	}
+	defer out.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(out, hash), resp.Body); err != nil {
+		return out.Name(), fmt.Errorf("Could not copy zip contents to temp file for op client: %w", err)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(sum, checksum) {
+		return out.Name(), fmt.Errorf("Checksum mismatch for op client v%s: got %s, want %s", version, sum, checksum)
+	}
+	return out.Name(), nil
+}
+
+func unzip(src string, dest string) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		traversableCheck := strings.Split(f.Name, "..")
+		fpath := filepath.Join(dest, traversableCheck[len(traversableCheck)-1])
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(fpath, filepath.Clean(dest)+string(os.PathSeparator)) {
+			return fmt.Errorf("%s: illegal file path", fpath)
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(fpath, os.ModePerm); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
+			return err
+		}
+		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+		if err != nil {
+			return err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(outFile, rc)
+		outFile.Close()
+		rc.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
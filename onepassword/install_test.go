@@ -0,0 +1,238 @@
+package onepassword
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func fixtureOPZip(t *testing.T, content string) []byte {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	f, err := w.Create("op")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_opChecksums(t *testing.T) {
+	for _, platform := range opInstallPlatforms {
+		t.Run(platform, func(t *testing.T) {
+			spl := strings.SplitN(platform, "_", 2)
+			checksum, ok := opChecksums[opChecksumKey(defaultOPVersion, spl[0], spl[1])]
+			if !ok {
+				// the release archives can't be fetched everywhere the tests run,
+				// a missing pin must not pass silently on CI though
+				if os.Getenv("CI") == "true" {
+					t.Fatalf("no checksum pinned for v%s of op on %s", defaultOPVersion, platform)
+				}
+				t.Skipf("no checksum pinned for v%s of op on %s", defaultOPVersion, platform)
+			}
+			if b, err := hex.DecodeString(checksum); err != nil || len(b) != sha256.Size {
+				t.Errorf("checksum of v%s on %s = %q, want a SHA256 hex digest", defaultOPVersion, platform, checksum)
+			}
+		})
+	}
+}
+
+func Test_installOPClient(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("op client is not installed automatically on darwin")
+	}
+
+	archive := fixtureOPZip(t, "#!/bin/sh\necho fake op\n")
+	sum := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(sum[:])
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if _, err := w.Write(archive); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	defaultURL := opDownloadURL
+	opDownloadURL = server.URL + "/v%[1]s/op_%[2]s_%[3]s.zip"
+	defer func() { opDownloadURL = defaultURL }()
+
+	tests := []struct {
+		name         string
+		checksum     string
+		wantErr      bool
+		wantRequests int
+	}{
+		{
+			name:         "no checksum",
+			wantErr:      true,
+			wantRequests: 0,
+		},
+		{
+			name:         "checksum mismatch",
+			checksum:     "0000000000000000000000000000000000000000000000000000000000000000",
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name:         "verified",
+			checksum:     checksum,
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := t.TempDir()
+			t.Setenv("XDG_CACHE_HOME", cache)
+			requests = 0
+
+			got, err := installOPClient(context.Background(), defaultOPVersion, tt.checksum)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("installOPClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("installOPClient() requests = %d, want %d", requests, tt.wantRequests)
+			}
+
+			bin := filepath.Join(cache, "terraform-provider-onepassword", defaultOPVersion, "op")
+			if tt.wantErr {
+				if _, err := os.Stat(bin); !os.IsNotExist(err) {
+					t.Errorf("installOPClient() left a binary behind: %v", err)
+				}
+				return
+			}
+			if got != bin {
+				t.Errorf("installOPClient() = %s, want %s", got, bin)
+			}
+			if content, err := ioutil.ReadFile(got); err != nil || string(content) != "#!/bin/sh\necho fake op\n" {
+				t.Errorf("installOPClient() installed unexpected binary: %s, %v", content, err)
+			}
+			info, err := os.Stat(filepath.Dir(bin))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != 0700 {
+				t.Errorf("installOPClient() cache dir mode = %v, want 0700", info.Mode().Perm())
+			}
+
+			if _, err := installOPClient(context.Background(), defaultOPVersion, tt.checksum); err != nil {
+				t.Fatal(err)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("installOPClient() downloaded cached binary again")
+			}
+
+			if err := ioutil.WriteFile(bin, []byte("#!/bin/sh\necho tampered\n"), 0700); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := installOPClient(context.Background(), defaultOPVersion, tt.checksum); err != nil {
+				t.Fatal(err)
+			}
+			if requests != tt.wantRequests+1 {
+				t.Errorf("installOPClient() reused a modified cached binary")
+			}
+			if content, err := ioutil.ReadFile(bin); err != nil || string(content) != "#!/bin/sh\necho fake op\n" {
+				t.Errorf("installOPClient() kept modified binary: %s, %v", content, err)
+			}
+		})
+	}
+}
+
+func fakeOPBinary(t *testing.T, dir string, version string) string {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	bin := filepath.Join(dir, "op")
+	if err := ioutil.WriteFile(bin, []byte("#!/bin/sh\necho "+version+"\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return bin
+}
+
+func Test_findOPClient(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake op binaries are shell scripts")
+	}
+	t.Setenv("PATH", t.TempDir())
+	root := t.TempDir()
+	old := fakeOPBinary(t, filepath.Join(root, "old"), "1.2.0")
+	current := fakeOPBinary(t, filepath.Join(root, "current"), "1.5.0")
+
+	tests := []struct {
+		name       string
+		candidates []string
+		min        string
+		max        string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "first matching binary",
+			candidates: []string{old, current},
+			min:        "1.4.0",
+			want:       current,
+		},
+		{
+			name:       "directory candidate",
+			candidates: []string{filepath.Join(root, "current")},
+			min:        "1.4.0",
+			want:       current,
+		},
+		{
+			name:       "above max version",
+			candidates: []string{current},
+			min:        "1.0.0",
+			max:        "1.4.0",
+			wantErr:    true,
+		},
+		{
+			name:       "nothing found",
+			candidates: []string{filepath.Join(root, "missing")},
+			min:        "1.4.0",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := newOPVersionPolicy(tt.min, tt.max)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, v, err := findOPClient(context.Background(), tt.candidates, policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findOPClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				for _, candidate := range tt.candidates {
+					if !strings.Contains(err.Error(), candidate) {
+						t.Errorf("findOPClient() error doesn't list %s: %v", candidate, err)
+					}
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("findOPClient() = %s, want %s", got, tt.want)
+			}
+			if v.String() != "1.5.0" {
+				t.Errorf("findOPClient() version = %s, want 1.5.0", v)
+			}
+		})
+	}
+}
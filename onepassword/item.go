@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os/exec"
+	"log"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -63,7 +65,10 @@ const (
 	TypeReference SectionFieldType = "reference"
 )
 
-const IsTrashed = "Y"
+const (
+	IsTrashed    = "Y"
+	IsNotTrashed = "N"
+)
 
 type Address struct {
 	City    string `json:"city"`
@@ -132,12 +137,18 @@ type DocumentAttributes struct {
 }
 
 type Overview struct {
-	Title string   `json:"title"`
-	URL   string   `json:"url"`
-	Tags  []string `json:"tags"`
+	Title string    `json:"title"`
+	URL   string    `json:"url"`
+	URLs  []ItemURL `json:"URLs,omitempty"`
+	Tags  []string  `json:"tags"`
 }
 
-func (o *OnePassClient) ReadItem(id string, vaultID string) (*Item, error) {
+type ItemURL struct {
+	Label string `json:"l"`
+	Href  string `json:"u"`
+}
+
+func (o *OnePassClient) ReadItem(ctx context.Context, id string, vaultID string) (*Item, error) {
 	item := &Item{}
 	args := []string{
 		opPasswordGet,
@@ -148,12 +159,13 @@ func (o *OnePassClient) ReadItem(id string, vaultID string) (*Item, error) {
 	if vaultID != "" {
 		args = append(args, fmt.Sprintf("--vault=%s", vaultID))
 	}
-	res, err := o.RunSimpleCmd(args...)
+	res, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
-		if itemNotFound(string(res), err) {
+		err = prettyError(args, res, err)
+		if errors.Is(err, ErrNotFound) {
 			return nil, nil
 		}
-		return nil, prettyError(args, res, err)
+		return nil, err
 	}
 	if err = json.Unmarshal(res, item); err != nil {
 		return nil, err
@@ -161,12 +173,193 @@ func (o *OnePassClient) ReadItem(id string, vaultID string) (*Item, error) {
 	return item, nil
 }
 
-func itemNotFound(res string, err error) bool {
-	if exitError, ok := err.(*exec.ExitError); ok {
-		return (exitError.ExitCode() == 1 && strings.Contains(res, "isn't an item in")) ||
-			(exitError.ExitCode() == 4 && strings.Contains(res, "The requested resource was not found"))
+// ListItems returns overviews of all items in the vault, details are not included
+func (o *OnePassClient) ListItems(ctx context.Context, vaultID string) ([]Item, error) {
+	return o.listItems(ctx, vaultID)
+}
+
+// ListAllItems lists the items including the archived ones, op calls the archive trash
+func (o *OnePassClient) ListAllItems(ctx context.Context, vaultID string) ([]Item, error) {
+	return o.listItems(ctx, vaultID, "--include-trash")
+}
+
+func (o *OnePassClient) listItems(ctx context.Context, vaultID string, flags ...string) ([]Item, error) {
+	items := []Item{}
+	args := []string{opPasswordList, "items"}
+	if vaultID != "" {
+		args = append(args, fmt.Sprintf("--vault=%s", vaultID))
+	}
+	args = append(args, flags...)
+	res, err := o.RunSimpleCmd(ctx, args...)
+	if err != nil {
+		return nil, prettyError(args, res, err)
+	}
+	if err = json.Unmarshal(res, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ImportReferencePrefix starts secret references, e.g. op://vault/item
+const ImportReferencePrefix = "op://"
+
+// parseItemImportID splits the import ID into vault and item, the ID is either
+// a UUID or title, "vault/item" or "op://vault/item"
+func parseItemImportID(id string) (vault string, item string, err error) {
+	ref := strings.TrimPrefix(id, ImportReferencePrefix)
+	if ref != id {
+		spl := strings.Split(ref, "/")
+		if len(spl) != 2 || spl[0] == "" || spl[1] == "" {
+			return "", "", fmt.Errorf("%s is not an item reference, the format \"op://vault/item\" is expected", id)
+		}
+		return spl[0], spl[1], nil
+	}
+	spl := strings.SplitN(id, "/", 2)
+	if len(spl) == 1 {
+		return "", id, nil
+	}
+	if spl[0] == "" || spl[1] == "" {
+		return "", "", fmt.Errorf("%s is not an item path, the format \"vault/item\" is expected", id)
+	}
+	return spl[0], spl[1], nil
+}
+
+// itemUUID matches the UUIDs 1Password generates for items
+var itemUUID = regexp.MustCompile(`^[a-z0-9]{26}$`)
+
+// FindItem looks up the item by UUID or title among the items of the vault, or of all vaults
+// when vaultID is empty. An error is returned when the title matches several items.
+// Archived items aren't listed, so they're read directly when the id looks like a UUID
+// or nothing else matches.
+func (o *OnePassClient) FindItem(ctx context.Context, id string, vaultID string) (*Item, error) {
+	if itemUUID.MatchString(id) {
+		v, err := o.ReadItem(ctx, id, vaultID)
+		if err != nil || v != nil {
+			return v, err
+		}
+	}
+	items, err := o.ListItems(ctx, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	var found []Item
+	for _, item := range items {
+		if item.UUID == id {
+			return &item, nil
+		}
+		if item.Overview.Title == id {
+			found = append(found, item)
+		}
+	}
+	where := "any vault"
+	if vaultID != "" {
+		where = "vault " + vaultID
+	}
+	switch len(found) {
+	case 0:
+		v, err := o.ReadItem(ctx, id, vaultID)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			return nil, fmt.Errorf("item %s not found in %s", id, where)
+		}
+		return v, nil
+	case 1:
+		return &found[0], nil
+	}
+	ids := make([]string, 0, len(found))
+	for _, item := range found {
+		ids = append(ids, item.UUID)
+	}
+	return nil, fmt.Errorf("item title %s is ambiguous in %s, use one of the UUIDs instead: %s", id, where, strings.Join(ids, ", "))
+}
+
+// categoryResource returns the resource type which manages items of the category
+func categoryResource(c Category) string {
+	switch c {
+	case LoginCategory:
+		return "onepassword_item_login"
+	case PasswordCategory:
+		return "onepassword_item_password"
+	case IdentityCategory:
+		return "onepassword_item_identity"
+	case CreditCardCategory:
+		return "onepassword_item_credit_card"
+	case SecureNoteCategory:
+		return "onepassword_item_secure_note"
+	case SoftwareLicenseCategory:
+		return "onepassword_item_software_license"
+	case DocumentCategory:
+		return "onepassword_item_document"
+	default:
+		return "onepassword_item_common"
+	}
+}
+
+// checkItemCategory returns an error pointing to the right resource type
+// when the item isn't from one of the categories
+func checkItemCategory(v *Item, categories ...Category) error {
+	c := Template2Category(v.Template)
+	for _, category := range categories {
+		if c == category {
+			return nil
+		}
+	}
+	if c == UnknownCategory {
+		return fmt.Errorf("item %s has unsupported template %s", v.UUID, v.Template)
+	}
+	return fmt.Errorf("item %s is from %s category, use %s resource instead", v.UUID, c, categoryResource(c))
+}
+
+// itemFlattenFunc sets the attributes of the resource or data source from the item
+type itemFlattenFunc func(ctx context.Context, d *schema.ResourceData, meta interface{}, v *Item) diag.Diagnostics
+
+// readItem reads the item and sets the attributes with flatten,
+// the ID is cleared when the item doesn't exist anymore
+func readItem(ctx context.Context, d *schema.ResourceData, meta interface{}, flatten itemFlattenFunc) diag.Diagnostics {
+	m := meta.(*Meta)
+	vaultID := d.Get("vault").(string)
+	v, err := m.onePassClient.ReadItem(ctx, getID(d), vaultID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if v == nil {
+		log.Printf("[INFO] Item %s not found in %s vault", getID(d), vaultID)
+		d.SetId("")
+		return nil
+	}
+	return flatten(ctx, d, meta, v)
+}
+
+// resourceItemImporter resolves the import ID to the item UUID and checks
+// the item category before reading it
+func resourceItemImporter(read schema.ReadContextFunc, categories ...Category) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		vaultID, id, err := parseItemImportID(d.Id())
+		if err != nil {
+			return nil, err
+		}
+		m := meta.(*Meta)
+		v, err := m.onePassClient.FindItem(ctx, id, vaultID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkItemCategory(v, categories...); err != nil {
+			return nil, err
+		}
+		d.SetId(v.UUID)
+		if err := d.Set("vault", v.Vault); err != nil {
+			return nil, err
+		}
+		if diags := read(ctx, d, meta); diags.HasError() {
+			return nil, errors.New(diags[0].Summary)
+		}
+		if d.Id() == "" {
+			return nil, fmt.Errorf("item %s not found", v.UUID)
+		}
+		return []*schema.ResourceData{d}, nil
 	}
-	return false
 }
 
 func Category2Template(c Category) string {
@@ -255,8 +448,11 @@ func Template2Category(t string) Category {
 	}
 }
 
-func (o *OnePassClient) CreateItem(v *Item) error {
-	details, err := json.Marshal(v.Details)
+func (o *OnePassClient) CreateItem(ctx context.Context, v *Item) error {
+	details, err := json.Marshal(struct {
+		Details
+		URLs []ItemURL `json:"URLs,omitempty"`
+	}{v.Details, v.Overview.URLs})
 	if err != nil {
 		return err
 	}
@@ -270,7 +466,7 @@ func (o *OnePassClient) CreateItem(v *Item) error {
 		opPasswordCreate,
 		ItemResource,
 		string(template),
-		detailsHash,
+		Stdin,
 	}
 
 	if v.Vault != "" {
@@ -289,26 +485,27 @@ func (o *OnePassClient) CreateItem(v *Item) error {
 		args = append(args, fmt.Sprintf("--tags=%s", strings.Join(v.Overview.Tags, ",")))
 	}
 
-	res, err := o.RunSimpleCmd(args...)
-	if err == nil {
-		if id, err := getResultID(res); err == nil {
-			v.UUID = id
-		}
-		return err
+	// item details contain secrets, so they are passed over stdin to keep them out of the process list
+	res, err := o.RunStdinCmd(ctx, []byte(detailsHash), args...)
+	if err != nil {
+		return prettyError(args, res, err)
+	}
+	if id, err := getResultID(res); err == nil {
+		v.UUID = id
 	}
-	return prettyError(args, res, err)
+	return nil
 }
 
-func (o *OnePassClient) ReadDocument(id string) ([]byte, error) {
+func (o *OnePassClient) ReadDocument(ctx context.Context, id string) ([]byte, error) {
 	args := []string{opPasswordGet, DocumentResource, id}
-	content, err := o.RunSimpleCmd(args...)
+	content, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
 		return content, prettyError(args, content, err)
 	}
 	return content, err
 }
 
-func (o *OnePassClient) CreateDocument(v *Item, content []byte) error {
+func (o *OnePassClient) CreateDocument(ctx context.Context, v *Item, content []byte) error {
 	args := []string{
 		opPasswordCreate,
 		DocumentResource,
@@ -325,19 +522,62 @@ func (o *OnePassClient) CreateDocument(v *Item, content []byte) error {
 		args = append(args, fmt.Sprintf("--vault=%s", v.Vault))
 	}
 
-	res, err := o.RunStdinCmd(content, args...)
-	if err == nil {
-		if id, err := getResultID(res); err == nil {
-			v.UUID = id
+	res, err := o.RunStdinCmd(ctx, content, args...)
+	if err != nil {
+		return prettyError(args, res, err)
+	}
+	if id, err := getResultID(res); err == nil {
+		v.UUID = id
+	}
+	return nil
+}
+
+// archiveCreatedItem archives the item just created when archived is set.
+// It's called after the ID is set, so the item stays in the state when archiving fails.
+func archiveCreatedItem(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if !d.Get("archived").(bool) {
+		return nil
+	}
+	m := meta.(*Meta)
+	if err := m.onePassClient.ArchiveItem(ctx, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// resourceItemCustomizeDiff rejects moving an archived item back to its vault,
+// op has no command to restore items from the archive
+func resourceItemCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("archived") {
+		return nil
+	}
+	if old, _ := d.GetChange("archived"); old.(bool) {
+		return fmt.Errorf("item %s can't be restored from the archive with op, restore it in a 1Password app and refresh the state", d.Id())
+	}
+	return nil
+}
+
+func resourceItemUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	m := meta.(*Meta)
+	if d.HasChange("archived") && d.Get("archived").(bool) {
+		if err := m.onePassClient.ArchiveItem(ctx, d.Id()); err != nil {
+			return diag.FromErr(err)
 		}
-		return err
 	}
-	return prettyError(args, res, err)
+	return nil
 }
 
 func resourceItemDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("item %s has deletion_protection enabled, set it to false and apply before destroying the item", getID(d))
+	}
 	m := meta.(*Meta)
-	err := m.onePassClient.DeleteItem(getID(d))
+	var err error
+	if !d.Get("archive_on_destroy").(bool) {
+		err = m.onePassClient.DeleteItem(ctx, getID(d))
+	} else if !d.Get("archived").(bool) {
+		err = m.onePassClient.ArchiveItem(ctx, getID(d))
+	}
 	if err == nil {
 		d.SetId("")
 		return nil
@@ -345,45 +585,134 @@ func resourceItemDelete(ctx context.Context, d *schema.ResourceData, meta interf
 	return diag.FromErr(err)
 }
 
-func (o *OnePassClient) DeleteItem(id string) error {
-	return o.Delete(ItemResource, id)
+func (o *OnePassClient) DeleteItem(ctx context.Context, id string) error {
+	return o.Delete(ctx, ItemResource, id)
+}
+
+// ArchiveItem moves an item to the archive, op can't restore it from there
+func (o *OnePassClient) ArchiveItem(ctx context.Context, id string) error {
+	args := []string{opPasswordDelete, ItemResource, id, "--archive"}
+	res, err := o.RunSimpleCmd(ctx, args...)
+	if err != nil {
+		return prettyError(args, res, err)
+	}
+	return nil
+}
+
+// fieldKey returns the attribute of the section field schema holding the field value
+func fieldKey(field SectionField) string {
+	switch field.Type {
+	case TypeSex:
+		return "sex"
+	case TypeURL:
+		return "url"
+	case TypeMonthYear:
+		return "month_year"
+	case TypeCard:
+		return "card_type"
+	case TypeConcealed:
+		if strings.HasPrefix(field.N, "TOTP_") {
+			return "totp"
+		}
+		return "concealed"
+	default:
+		return string(field.Type)
+	}
 }
 
 func ProcessField(srcFields []SectionField) []map[string]interface{} {
 	fields := make([]map[string]interface{}, 0, len(srcFields))
 	for _, field := range srcFields {
 		f := map[string]interface{}{
+			"id":   field.N,
 			"name": field.Text,
 		}
-		var key string
 		switch field.Type {
-		case TypeSex:
-			key = "sex"
-		case TypeURL:
-			key = "url"
+		case TypeAddress:
+			f["address"] = ProcessAddress(field.Value)
+		case TypeDate:
+			f["date"] = FormatDate(dateTimeValue(field.Value))
+			f["date_raw"] = dateTimeValue(field.Value)
 		case TypeMonthYear:
-			key = "month_year"
-		case TypeCard:
-			key = "card_type"
-		case TypeConcealed:
-			if strings.HasPrefix(field.N, "TOTP_") {
-				key = "totp"
-			} else {
-				key = "concealed"
-			}
+			f["month_year"] = FormatMonthYear(dateTimeValue(field.Value))
+			f["month_year_raw"] = dateTimeValue(field.Value)
 		default:
-			key = string(field.Type)
+			f[fieldKey(field)] = field.Value
 		}
-		f[key] = field.Value
 		fields = append(fields, f)
 	}
 	return fields
 }
 
+// ProcessAddress converts 1Password's address structure to the list of one block the schema
+// expects, an empty list is returned when the address has no parts set.
+func ProcessAddress(v interface{}) []interface{} {
+	src, _ := v.(map[string]interface{})
+	address := map[string]interface{}{}
+	empty := true
+	for _, k := range addressKeys {
+		address[k] = fieldValueString(src[k])
+		empty = empty && address[k] == ""
+	}
+	if empty {
+		return []interface{}{}
+	}
+	return []interface{}{address}
+}
+
+// ProcessFieldMap flattens the item fields into "section.field" keys, fields of untitled
+// sections and the username and password of logins are keyed by the field name only.
+// Address fields are split into "section.field.city" and alike.
+func ProcessFieldMap(details Details) (values map[string]string, types map[string]string) {
+	values = map[string]string{}
+	types = map[string]string{}
+	for _, field := range details.Fields {
+		if field.Name == "" {
+			continue
+		}
+		values[field.Name] = field.Value
+		types[field.Name] = string(TypeString)
+		if field.Type == FieldPassword {
+			types[field.Name] = string(TypeConcealed)
+		}
+	}
+	for _, section := range details.Sections {
+		for _, field := range section.Fields {
+			key := field.Text
+			if section.Title != "" {
+				key = section.Title + "." + key
+			}
+			if address, ok := field.Value.(map[string]interface{}); ok {
+				for k, v := range address {
+					values[key+"."+k] = fieldValueString(v)
+					types[key+"."+k] = "address"
+				}
+				continue
+			}
+			values[key] = fieldValueString(field.Value)
+			types[key] = fieldKey(field)
+		}
+	}
+	return values, types
+}
+
+func fieldValueString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		// dates are unix timestamps, keep them out of the exponent notation
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
 func ProcessSections(srcSections []Section) []map[string]interface{} {
 	sections := make([]map[string]interface{}, 0, len(srcSections))
 	for _, section := range srcSections {
 		sections = append(sections, map[string]interface{}{
+			"id":    section.Name,
 			"name":  section.Title,
 			"field": ProcessField(section.Fields),
 		})
@@ -391,6 +720,24 @@ func ProcessSections(srcSections []Section) []map[string]interface{} {
 	return sections
 }
 
+// ProcessURLs returns the urls block of the item. 1Password only keeps the href of
+// the primary URL, so the first URL with that href is marked as primary.
+func ProcessURLs(o Overview) []map[string]interface{} {
+	urls := make([]map[string]interface{}, 0, len(o.URLs))
+	primary := -1
+	for i, u := range o.URLs {
+		if primary < 0 && u.Href == o.URL {
+			primary = i
+		}
+		urls = append(urls, map[string]interface{}{
+			"href":    u.Href,
+			"label":   u.Label,
+			"primary": i == primary,
+		})
+	}
+	return urls
+}
+
 func parseSectionFromSchema(sections []Section, d *schema.ResourceData, groups []SectionGroup) error {
 	leftSections := []Section{}
 	for _, section := range sections {
@@ -406,7 +753,18 @@ func parseSectionFromSchema(sections []Section, d *schema.ResourceData, groups [
 					found := false
 					for k, f := range group.Fields {
 						if f == field.N {
-							src[k] = field.Value
+							switch field.Type {
+							case TypeAddress:
+								src[k] = ProcessAddress(field.Value)
+							case TypeDate:
+								src[k] = FormatDate(dateTimeValue(field.Value))
+								src[k+"_raw"] = dateTimeValue(field.Value)
+							case TypeMonthYear:
+								src[k] = FormatMonthYear(dateTimeValue(field.Value))
+								src[k+"_raw"] = dateTimeValue(field.Value)
+							default:
+								src[k] = field.Value
+							}
 							found = true
 							continue
 						}
@@ -0,0 +1,255 @@
+package onepassword
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestOnePassClient_CreateItem(t *testing.T) {
+	type fields struct {
+		runCmd func() (string, error)
+	}
+	type args struct {
+		v *Item
+	}
+	tests := []struct {
+		name            string
+		fields          fields
+		args            args
+		wantExecResults []string
+		wantUUID        string
+		wantErr         bool
+	}{
+		{
+			name: "success",
+			fields: fields{
+				runCmd: func() (string, error) {
+					return `{ "uuid": "uniq" }`, nil
+				},
+			},
+			args: args{v: &Item{
+				Template: Category2Template(PasswordCategory),
+				Vault:    "vault",
+				Overview: Overview{Title: "foo"},
+				Details:  Details{Password: "super secret"},
+			}},
+			wantExecResults: []string{"op", "create", "item", "Password", "-", "--vault=vault", "--title=foo"},
+			wantUUID:        "uniq",
+		},
+		{
+			name: "error",
+			fields: fields{
+				runCmd: func() (string, error) {
+					return ``, fmt.Errorf("oops")
+				},
+			},
+			args: args{v: &Item{
+				Template: Category2Template(PasswordCategory),
+				Overview: Overview{Title: "foo"},
+				Details:  Details{Password: "super secret"},
+			}},
+			wantExecResults: []string{"op", "create", "item", "Password", "-", "--title=foo"},
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &mockOnePassConfig{
+				runCmd: tt.fields.runCmd,
+			}
+			o := mockOnePassClient(config)
+
+			err := o.CreateItem(context.Background(), tt.args.v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("OnePassClient.CreateItem() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.args.v.UUID != tt.wantUUID {
+				t.Errorf("OnePassClient.CreateItem() uuid = %v, want %v", tt.args.v.UUID, tt.wantUUID)
+			}
+			if !reflect.DeepEqual(config.execCommandResults, tt.wantExecResults) {
+				t.Errorf("OnePassClient.CreateItem() = %v, want %v", config.execCommandResults, tt.wantExecResults)
+			}
+		})
+	}
+}
+
+func Test_resourceItemCustomizeDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		archived string
+		raw      map[string]interface{}
+		wantErr  bool
+	}{
+		{name: "archive", archived: "false", raw: map[string]interface{}{"archived": true}},
+		{name: "keep archived", archived: "true", raw: map[string]interface{}{"archived": true}},
+		{name: "restore", archived: "true", raw: map[string]interface{}{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &terraform.InstanceState{ID: "note", Attributes: map[string]string{
+				"id":       "note",
+				"archived": tt.archived,
+			}}
+			_, err := resourceItemSecureNote().Diff(context.Background(), state, terraform.NewResourceConfigRaw(tt.raw), nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_parseItemImportID(t *testing.T) {
+	tests := []struct {
+		id        string
+		wantVault string
+		wantItem  string
+		wantErr   bool
+	}{
+		{id: "xmbxgtfhajgkrkkt4nhqtxh2ky", wantItem: "xmbxgtfhajgkrkkt4nhqtxh2ky"},
+		{id: "ops/db", wantVault: "ops", wantItem: "db"},
+		{id: "ops/aws/prod", wantVault: "ops", wantItem: "aws/prod"},
+		{id: "op://ops/db", wantVault: "ops", wantItem: "db"},
+		{id: "op://ops/db/password", wantErr: true},
+		{id: "/db", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			vault, item, err := parseItemImportID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseItemImportID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if vault != tt.wantVault || item != tt.wantItem {
+				t.Errorf("parseItemImportID() = %s, %s, want %s, %s", vault, item, tt.wantVault, tt.wantItem)
+			}
+		})
+	}
+}
+
+func Test_resourceItemImporter(t *testing.T) {
+	items := `[
+		{ "uuid": "login1", "templateUUID": "001", "vaultUUID": "ops", "overview": { "title": "db" } },
+		{ "uuid": "login2", "templateUUID": "001", "vaultUUID": "dev", "overview": { "title": "db" } },
+		{ "uuid": "note1", "templateUUID": "003", "vaultUUID": "ops", "overview": { "title": "readme" } }
+	]`
+	// archived items aren't listed, but can be read
+	archived := `{ "uuid": "archived1", "templateUUID": "001", "vaultUUID": "ops", "trashed": "Y", "overview": { "title": "old db" } }`
+	tests := []struct {
+		name    string
+		id      string
+		wantID  string
+		wantErr string
+	}{
+		{name: "uuid", id: "login2", wantID: "login2"},
+		{name: "vault path", id: "ops/db", wantID: "login1"},
+		{name: "reference", id: "op://dev/db", wantID: "login2"},
+		{name: "ambiguous title", id: "db", wantErr: "ambiguous"},
+		{name: "missing", id: "ops/cache", wantErr: "not found"},
+		{name: "wrong category", id: "ops/readme", wantErr: "onepassword_item_secure_note"},
+		{name: "archived", id: "ops/archived1", wantID: "archived1"},
+		{name: "uuid read directly", id: "xmbxgtfhajgkrkkt4nhqtxh2ky", wantID: "xmbxgtfhajgkrkkt4nhqtxh2ky"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &mockOnePassConfig{}
+			config.runCmd = func() (string, error) {
+				args := config.execCommandResults
+				if args[1] == opPasswordGet {
+					switch args[3] {
+					case "login1", "login2", "xmbxgtfhajgkrkkt4nhqtxh2ky":
+						return fmt.Sprintf(`{ "uuid": "%s", "templateUUID": "001", "overview": { "title": "db" } }`, args[3]), nil
+					case "archived1":
+						return archived, nil
+					}
+					return "", fmt.Errorf("The requested resource was not found")
+				}
+				var vault string
+				if len(args) > 3 {
+					vault = strings.TrimPrefix(args[3], "--vault=")
+				}
+				all := []Item{}
+				if err := json.Unmarshal([]byte(items), &all); err != nil {
+					return "", err
+				}
+				found := []Item{}
+				for _, item := range all {
+					if vault == "" || item.Vault == vault {
+						found = append(found, item)
+					}
+				}
+				out, err := json.Marshal(found)
+				return string(out), err
+			}
+			m := &Meta{onePassClient: mockOnePassClient(config)}
+			d := schema.TestResourceDataRaw(t, resourceItemLogin().Schema, map[string]interface{}{})
+			d.SetId(tt.id)
+
+			got, err := resourceItemImporter(resourceItemLoginRead, LoginCategory)(context.Background(), d, m)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resourceItemImporter() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got[0].Id() != tt.wantID {
+				t.Errorf("resourceItemImporter() id = %s, want %s", got[0].Id(), tt.wantID)
+			}
+		})
+	}
+}
+
+func TestProcessFieldMap(t *testing.T) {
+	details := Details{
+		Fields: []Field{
+			{Name: "username", Value: "admin", Type: FieldText},
+			{Name: "password", Value: "hunter2", Type: FieldPassword},
+		},
+		Sections: []Section{
+			{Name: "Section_1", Title: "db", Fields: []SectionField{
+				{Type: TypeString, Text: "host", Value: "db.example.com", N: "F1"},
+				{Type: TypeConcealed, Text: "otp", Value: "otpauth://totp/db", N: "TOTP_F2"},
+				{Type: TypeDate, Text: "created", Value: float64(1600000000), N: "F3"},
+				{Type: TypeAddress, Text: "office", Value: map[string]interface{}{"city": "Kyiv"}, N: "F4"},
+			}},
+			{Name: "", Title: "", Fields: []SectionField{
+				{Type: TypeURL, Text: "site", Value: "https://example.com", N: "F5"},
+			}},
+		},
+	}
+
+	values, types := ProcessFieldMap(details)
+	wantValues := map[string]string{
+		"username":       "admin",
+		"password":       "hunter2",
+		"db.host":        "db.example.com",
+		"db.otp":         "otpauth://totp/db",
+		"db.created":     "1600000000",
+		"db.office.city": "Kyiv",
+		"site":           "https://example.com",
+	}
+	wantTypes := map[string]string{
+		"username":       "string",
+		"password":       "concealed",
+		"db.host":        "string",
+		"db.otp":         "totp",
+		"db.created":     "date",
+		"db.office.city": "address",
+		"site":           "url",
+	}
+	if !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("ProcessFieldMap() values = %v, want %v", values, wantValues)
+	}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Errorf("ProcessFieldMap() types = %v, want %v", types, wantTypes)
+	}
+}
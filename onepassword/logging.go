@@ -0,0 +1,97 @@
+package onepassword
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const redacted = "[REDACTED]"
+
+// loggedFlags are op flags whose values are safe to log, values of any other flag are redacted
+var loggedFlags = map[string]bool{
+	"--archive":    true,
+	"--categories": true,
+	"--config":     true,
+	"--group":      true,
+	"--output":     true,
+	"--shorthand":  true,
+	"--vault":      true,
+}
+
+// identifierArg matches positional arguments which are UUIDs, names or emails.
+// Anything else, like an item payload or a password, is redacted.
+var identifierArg = regexp.MustCompile(`^[A-Za-z0-9_.@-]{1,64}$`)
+
+// redactArgs returns op arguments safe to be logged
+func redactArgs(args []string) []string {
+	safe := make([]string, len(args))
+	for i, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			name := strings.SplitN(arg, "=", 2)[0]
+			if name != arg && !loggedFlags[name] {
+				arg = name + "=" + redacted
+			}
+		case i < 2 || arg == Stdin || identifierArg.MatchString(arg):
+		default:
+			arg = redacted
+		}
+		safe[i] = arg
+	}
+	return safe
+}
+
+// commandLogFields describes an op invocation as structured log arguments,
+// op output and stdin are never logged as they contain item details and documents
+func commandLogFields(args []string, stdin int, output []byte, duration time.Duration, err error) []interface{} {
+	safe := redactArgs(args)
+	fields := []interface{}{
+		"subcommand", safe[0],
+		"args", safe,
+		"duration_ms", duration.Milliseconds(),
+		"exit_code", exitCode(err),
+		"output_bytes", len(output),
+	}
+	if len(safe) > 1 {
+		fields = append(fields, "resource_type", safe[1])
+	}
+	if len(safe) > 2 && safe[0] != opPasswordCreate && !strings.HasPrefix(safe[2], "--") {
+		fields = append(fields, "id", safe[2])
+	}
+	if stdin > 0 {
+		fields = append(fields, "stdin_bytes", stdin)
+	}
+	if err != nil {
+		if kind := classifyError(output); kind != nil {
+			fields = append(fields, "error_kind", kind.Error())
+		}
+	}
+	return fields
+}
+
+func logCommand(ctx context.Context, args []string, stdin int, output []byte, duration time.Duration, err error) {
+	fields := commandLogFields(args, stdin, output, duration, err)
+	if err != nil {
+		tflog.Debug(ctx, "op command failed", fields...)
+		return
+	}
+	tflog.Debug(ctx, "op command finished", fields...)
+}
+
+// exitCode returns the exit code of op, -1 when op didn't exit on its own
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,71 @@
+package onepassword
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_redactArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "get item",
+			args: []string{"get", "item", "xmbxgtfhajgkrkkt4nhqtxh2ky", "--vault=ops", "--config=/tmp/op"},
+			want: []string{"get", "item", "xmbxgtfhajgkrkkt4nhqtxh2ky", "--vault=ops", "--config=/tmp/op"},
+		},
+		{
+			name: "create item over stdin",
+			args: []string{"create", "item", "Login", "-", "--vault=ops", "--title=db", "--url=https://example.com"},
+			want: []string{"create", "item", "Login", "-", "--vault=ops", "--title=[REDACTED]", "--url=[REDACTED]"},
+		},
+		{
+			name: "legacy item payload",
+			args: []string{"create", "item", "Login", strings.Repeat("eyJmaWVsZHMiOltdfQ", 5)},
+			want: []string{"create", "item", "Login", "[REDACTED]"},
+		},
+		{
+			name: "session and password flags",
+			args: []string{"get", "item", "db", "--session=secret", "--password=secret"},
+			want: []string{"get", "item", "db", "--session=[REDACTED]", "--password=[REDACTED]"},
+		},
+		{
+			name: "name with spaces",
+			args: []string{"create", "vault", "super secret"},
+			want: []string{"create", "vault", "[REDACTED]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redactArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commandLogFields(t *testing.T) {
+	output := []byte(`{"details":{"password":"hunter2"}}`)
+	fields := commandLogFields([]string{"get", "item", "db", "--vault=ops"}, 0, output, 1500*time.Millisecond, nil)
+
+	got := map[string]interface{}{}
+	for i := 0; i < len(fields); i += 2 {
+		got[fields[i].(string)] = fields[i+1]
+	}
+	want := map[string]interface{}{
+		"subcommand":    "get",
+		"resource_type": "item",
+		"id":            "db",
+		"args":          []string{"get", "item", "db", "--vault=ops"},
+		"duration_ms":   int64(1500),
+		"exit_code":     0,
+		"output_bytes":  len(output),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("commandLogFields() = %v, want %v", got, want)
+	}
+}
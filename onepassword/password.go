@@ -0,0 +1,155 @@
+package onepassword
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/sethvargo/go-diceware/diceware"
+)
+
+const (
+	// similar looking characters like l, I, O, 0 and 1 are left out
+	lowerChars  = "abcdefghijkmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	digitChars  = "23456789"
+	symbolChars = "!#$%&*+-.:;=?@^_~"
+)
+
+// PasswordRecipe describes how a password is generated, it follows the options
+// of the 1Password generator: random characters or a number of memorable words.
+// Words are picked from the EFF short wordlist of 1296 words, every word adds
+// about 10.3 bits of entropy and every digit appended to a word about 3 bits.
+type PasswordRecipe struct {
+	Length     int
+	Letters    bool
+	Digits     bool
+	Symbols    bool
+	Words      int
+	Separator  string
+	Capitalize bool
+}
+
+// Generate returns a new password made with crypto/rand
+func (r PasswordRecipe) Generate() (string, error) {
+	if r.Words > 0 {
+		return r.generateWords()
+	}
+	return r.generateChars()
+}
+
+// classes returns the character sets enabled by the recipe
+func (r PasswordRecipe) classes() []string {
+	var classes []string
+	if r.Letters {
+		classes = append(classes, lowerChars, upperChars)
+	}
+	if r.Digits {
+		classes = append(classes, digitChars)
+	}
+	if r.Symbols {
+		classes = append(classes, symbolChars)
+	}
+	return classes
+}
+
+// Validate checks that a random character password can be generated from the recipe,
+// memorable passwords are always valid
+func (r PasswordRecipe) Validate() error {
+	if r.Words > 0 {
+		return nil
+	}
+	classes := r.classes()
+	if len(classes) == 0 {
+		return errors.New("at least one of letters, digits or symbols has to be enabled")
+	}
+	if r.Length < len(classes) {
+		return fmt.Errorf("length %d is too short to contain a character of every enabled class, at least %d characters are needed", r.Length, len(classes))
+	}
+	return nil
+}
+
+// generateChars picks at least one character of every enabled class, so the password
+// always satisfies the recipe, and fills the rest from all of them
+func (r PasswordRecipe) generateChars() (string, error) {
+	if err := r.Validate(); err != nil {
+		return "", err
+	}
+
+	classes := r.classes()
+	all := strings.Join(classes, "")
+	password := make([]byte, 0, r.Length)
+	for i := 0; i < r.Length; i++ {
+		chars := all
+		if i < len(classes) {
+			chars = classes[i]
+		}
+		c, err := randomChar(chars)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	if err := shuffle(password); err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
+
+// generateWords joins words of the EFF short wordlist with the separator,
+// a digit is appended to every word when digits are enabled
+func (r PasswordRecipe) generateWords() (string, error) {
+	gen, err := diceware.NewGenerator(&diceware.GeneratorInput{WordList: diceware.WordListEffSmall()})
+	if err != nil {
+		return "", err
+	}
+	list, err := gen.Generate(r.Words)
+	if err != nil {
+		return "", err
+	}
+	words := make([]string, 0, r.Words)
+	for _, word := range list {
+		if r.Capitalize {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		if r.Digits {
+			c, err := randomChar(digitChars)
+			if err != nil {
+				return "", err
+			}
+			word += string(c)
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, r.Separator), nil
+}
+
+func randomInt(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	i, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[i], nil
+}
+
+// shuffle is the Fisher-Yates shuffle with crypto/rand
+func shuffle(b []byte) error {
+	for i := len(b) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return err
+		}
+		b[i], b[j] = b[j], b[i]
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package onepassword
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sethvargo/go-diceware/diceware"
+)
+
+// effShortWords is the EFF short wordlist, indexed by four dice rolls
+var effShortWords = func() map[string]bool {
+	words := map[string]bool{}
+	list := diceware.WordListEffSmall()
+	for i := 1111; i <= 6666; i++ {
+		if w := list.WordAt(i); w != "" {
+			words[w] = true
+		}
+	}
+	return words
+}()
+
+func TestPasswordRecipe_Generate(t *testing.T) {
+	tests := []struct {
+		name    string
+		recipe  PasswordRecipe
+		check   func(string) bool
+		wantErr bool
+	}{
+		{
+			name:   "all classes",
+			recipe: PasswordRecipe{Length: 4, Letters: true, Digits: true, Symbols: true},
+			check: func(p string) bool {
+				return len(p) == 4 &&
+					strings.ContainsAny(p, lowerChars) &&
+					strings.ContainsAny(p, upperChars) &&
+					strings.ContainsAny(p, digitChars) &&
+					strings.ContainsAny(p, symbolChars)
+			},
+		},
+		{
+			name:   "digits only",
+			recipe: PasswordRecipe{Length: 12, Digits: true},
+			check: func(p string) bool {
+				return len(p) == 12 && strings.Trim(p, digitChars) == ""
+			},
+		},
+		{
+			name:   "memorable",
+			recipe: PasswordRecipe{Words: 4, Separator: ".", Capitalize: true, Digits: true},
+			check: func(p string) bool {
+				words := strings.Split(p, ".")
+				for _, w := range words {
+					if strings.ToUpper(w[:1]) != w[:1] || !strings.ContainsAny(w[len(w)-1:], digitChars) {
+						return false
+					}
+					if !effShortWords[strings.ToLower(w[:len(w)-1])] {
+						return false
+					}
+				}
+				return len(words) == 4
+			},
+		},
+		{
+			name:    "no classes",
+			recipe:  PasswordRecipe{Length: 12},
+			wantErr: true,
+		},
+		{
+			name:    "too short",
+			recipe:  PasswordRecipe{Length: 2, Letters: true, Digits: true},
+			wantErr: true,
+		},
+		{
+			name:    "too short for letters",
+			recipe:  PasswordRecipe{Length: 1, Letters: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				got, err := tt.recipe.Generate()
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("PasswordRecipe.Generate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && !tt.check(got) {
+					t.Fatalf("PasswordRecipe.Generate() = %s doesn't follow the recipe", got)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceGeneratedPassword_CustomizeDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     map[string]interface{}
+		wantErr bool
+	}{
+		{name: "defaults", raw: map[string]interface{}{}},
+		{name: "letters only", raw: map[string]interface{}{"length": 2, "digits": false, "symbols": false}},
+		{name: "too short for letters", raw: map[string]interface{}{"length": 1, "digits": false, "symbols": false}, wantErr: true},
+		{name: "too short for all classes", raw: map[string]interface{}{"length": 3}, wantErr: true},
+		{name: "no classes", raw: map[string]interface{}{"letters": false, "digits": false, "symbols": false}, wantErr: true},
+		{name: "memorable ignores length", raw: map[string]interface{}{"length": 1, "words": 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resourceGeneratedPassword().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.raw), nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
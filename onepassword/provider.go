@@ -1,28 +1,27 @@
 package onepassword
 
 import (
-	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
+	"math/rand"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
+	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Masterminds/semver"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-var version string = "1.4.0"
-
 func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
@@ -55,8 +54,77 @@ func Provider() *schema.Provider {
 				},
 				Description: "Set alternative subdomain for 1password. From [subdomain].1password.com",
 			},
+			"sign_in_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OP_SIGN_IN_ADDRESS", ""),
+				Description: "Set full sign in address of the account, e.g. company.1password.eu, takes precedence over subdomain",
+			},
+			"account": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OP_ACCOUNT", ""),
+				Description: "Set account shorthand used by op for this provider, defaults to the first label of the sign in address. Session token is read from OP_SESSION_[account]",
+			},
+			"config_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OP_CONFIG_DIR", ""),
+				Description: "Set op configuration directory, by default every signed in account gets its own directory",
+			},
+			"op_binary_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OP_BINARY_PATH", ""),
+				Description: "Path to the op binary, skips looking for op on PATH and downloading it",
+			},
+			"op_path": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "op binaries or directories containing op, checked in order before looking for op on PATH",
+			},
+			"min_op_version": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("OP_VERSION", defaultOPVersion),
+				ValidateDiagFunc: semverValidateDiag(),
+				Description:      "Minimum accepted op version, it's also the version which is downloaded when op is missing",
+			},
+			"max_op_version": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: semverValidateDiag(),
+				Description:      "Maximum accepted op version",
+			},
+			"op_checksum": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OP_CHECKSUM", ""),
+				Description: "SHA256 checksum of the op release archive, required to download versions which are not pinned by the provider",
+			},
+			"max_retries": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OP_MAX_RETRIES", 3),
+				Description: "Maximum number of retries of op commands failed with rate limiting or network errors",
+			},
+			"retry_timeout": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("OP_RETRY_TIMEOUT", "5m"),
+				ValidateDiagFunc: durationValidateDiag(),
+				Description:      "Maximum time after which failed op commands aren't retried anymore, e.g. 30s or 5m",
+			},
+			"refuse_non_empty_vault_delete": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refuse to delete vaults which still contain items",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
+			"onepassword_generated_password":    resourceGeneratedPassword(),
 			"onepassword_group":                 resourceGroup(),
 			"onepassword_group_member":          resourceGroupMember(),
 			"onepassword_item_common":           resourceItemCommon(),
@@ -87,7 +155,7 @@ func Provider() *schema.Provider {
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	return NewMeta(d)
+	return NewMeta(ctx, d)
 }
 
 const (
@@ -101,24 +169,37 @@ const (
 )
 
 type OnePassClient struct {
-	Password    string
-	Email       string
-	SecretKey   string
-	Subdomain   string
-	PathToOp    string
-	Session     string
-	execCommand func(string, ...string) *exec.Cmd // Can be overridden for mocking purposes
-	mutex       *sync.Mutex
+	Password     string
+	Email        string
+	SecretKey    string
+	Address      string
+	Account      string
+	ConfigDir    string
+	PathToOp     string
+	Version      *semver.Version
+	Session      string
+	MaxRetries   int
+	RetryTimeout time.Duration
+	execCommand  func(context.Context, string, ...string) *exec.Cmd // Can be overridden for mocking purposes
+	mutex        *sync.Mutex
 }
 
+// defaultTimeout is used for create, read, update and delete unless a timeouts block overrides it
+const defaultTimeout = 5 * time.Minute
+
+// retryBaseDelay is the first backoff delay, it doubles with every attempt
+var retryBaseDelay = time.Second
+
+const retryMaxDelay = 30 * time.Second
+
 type Meta struct {
 	data          *schema.ResourceData
 	onePassClient *OnePassClient
 }
 
-func NewMeta(d *schema.ResourceData) (*Meta, diag.Diagnostics) {
+func NewMeta(ctx context.Context, d *schema.ResourceData) (*Meta, diag.Diagnostics) {
 	m := &Meta{data: d}
-	client, err := m.NewOnePassClient()
+	client, err := m.NewOnePassClient(ctx)
 	if err != nil {
 		return m, diag.FromErr(err)
 	}
@@ -126,183 +207,144 @@ func NewMeta(d *schema.ResourceData) (*Meta, diag.Diagnostics) {
 	return m, nil
 }
 
-func unzip(src string, dest string) error {
-	r, err := zip.OpenReader(src)
+func (m *Meta) NewOnePassClient(ctx context.Context) (*OnePassClient, error) {
+	bin, opVersion, err := m.opClient(ctx)
 	if err != nil {
-		return err
-	}
-	defer r.Close()
-
-	for _, f := range r.File {
-		traversableCheck := strings.Split(f.Name, "..")
-		fpath := filepath.Join(dest, traversableCheck[len(traversableCheck)-1])
-		if err != nil {
-			return err
-		}
-		if !strings.HasPrefix(fpath, filepath.Clean(dest)+string(os.PathSeparator)) {
-			return fmt.Errorf("%s: illegal file path", fpath)
-		}
-		if f.FileInfo().IsDir() {
-			if err := os.MkdirAll(fpath, os.ModePerm); err != nil {
-				return err
-			}
-			continue
-		}
-		if err = os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
-			return err
-		}
-		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			return err
-		}
-		rc, err := f.Open()
-		if err != nil {
-			return err
-		}
-		_, err = io.Copy(outFile, rc)
-		outFile.Close()
-		rc.Close()
-
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func findExistingOPClient() (string, error) {
-	o, err := exec.Command("op", "--version").Output()
-
-	if err != nil {
-		return "", fmt.Errorf("Trouble calling: op\nOutput: %s", o)
-	}
-
-	c, err := semver.NewConstraint(">= " + version)
-	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	v, err := semver.NewVersion(strings.TrimSuffix(string(o), "\n"))
+	address, err := signInAddress(m.data.Get("sign_in_address").(string), m.data.Get("subdomain").(string))
 	if err != nil {
-		return "", fmt.Errorf("[%s]", string(o))
-	}
-
-	if c.Check(v) {
-		return "op", nil
-	}
-
-	return "", fmt.Errorf("op version needs to be equal or greater than: %s", version)
-}
-
-func installOPClient() (string, error) {
-	if os.Getenv("OP_VERSION") != "" {
-		semVer, err := semver.NewVersion(os.Getenv("OP_VERSION"))
-		if err != nil {
-			return "", err
-		}
-		version = semVer.String()
-	}
-	if runtime.GOOS == "darwin" {
-		return "", fmt.Errorf("Unable to automatically install v%s of the op client. Please install manually from https://app-updates.agilebits.com/product_history/CLI", version)
+		return nil, err
 	}
+	email := m.data.Get("email").(string)
+	password := m.data.Get("password").(string)
+	secretKey := m.data.Get("secret_key").(string)
+	account := m.data.Get("account").(string)
+	configDir := m.data.Get("config_dir").(string)
+	session := ""
 
-	binZip := fmt.Sprintf("/tmp/op_%s.zip", version)
-	if _, err := os.Stat(binZip); os.IsNotExist(err) {
-		resp, err := http.Get(fmt.Sprintf(
-			"https://cache.agilebits.com/dist/1P/op/pkg/v%s/op_%s_%s_v%s.zip",
-			version,
-			runtime.GOOS,
-			runtime.GOARCH,
-			version,
-		))
-		if err != nil {
-			return "", fmt.Errorf("Could not retrieve zipped op release: %w", err)
-		}
-		defer resp.Body.Close()
-
-		out, err := os.Create(binZip)
-		if err != nil {
-			return "", fmt.Errorf("Could not create temp file for op client: %w", err)
-		}
-		defer out.Close()
-		if _, err = io.Copy(out, resp.Body); err != nil {
-			return "", fmt.Errorf("Could not copy zip contents to temp file for op client: %w", err)
-		}
-		if err := unzip(binZip, "/tmp/terraform-provider-onepassword/"+version); err != nil {
-			return "", fmt.Errorf("Could not unzip temp file for op client: %w", err)
-		}
+	if account == "" {
+		account = accountShorthand(address)
 	}
-	return "/tmp/terraform-provider-onepassword/" + version + "/op", nil
-}
 
-func (m *Meta) NewOnePassClient() (*OnePassClient, error) {
-	bin, err := findExistingOPClient()
+	retryTimeout, err := time.ParseDuration(m.data.Get("retry_timeout").(string))
 	if err != nil {
-		bin, err = installOPClient()
-		if err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
 
-	subdomain := m.data.Get("subdomain").(string)
-	email := m.data.Get("email").(string)
-	password := m.data.Get("password").(string)
-	secretKey := m.data.Get("secret_key").(string)
-	session := ""
-
 	if email == "" || password == "" || secretKey == "" {
 		email = ""
 		password = ""
 		secretKey = ""
 
-		var sessionKeyName string
-		if strings.Contains(subdomain, "-") {
-			sessionKeyName = "OP_SESSION_" + strings.ReplaceAll(subdomain, "-", "_")
-		} else {
-			sessionKeyName = "OP_SESSION_" + subdomain
-		}
-		session = os.Getenv(sessionKeyName)
+		session = os.Getenv(sessionKeyName(account))
 
 		if session == "" {
 			return nil, fmt.Errorf("email, password or secret_key is empty and environment variable %s is not set",
-				sessionKeyName)
+				sessionKeyName(account))
+		}
+	} else if configDir == "" {
+		if configDir, err = opConfigDir(account, address, email); err != nil {
+			return nil, err
 		}
 	}
 
 	op := &OnePassClient{
-		Email:       email,
-		Password:    password,
-		SecretKey:   secretKey,
-		Subdomain:   subdomain,
-		PathToOp:    bin,
-		Session:     session,
-		execCommand: exec.Command,
-		mutex:       &sync.Mutex{},
+		Email:        email,
+		Password:     password,
+		SecretKey:    secretKey,
+		Address:      address,
+		Account:      account,
+		ConfigDir:    configDir,
+		PathToOp:     bin,
+		Version:      opVersion,
+		Session:      session,
+		MaxRetries:   m.data.Get("max_retries").(int),
+		RetryTimeout: retryTimeout,
+		execCommand:  exec.CommandContext,
+		mutex:        &sync.Mutex{},
 	}
 
 	if session != "" {
 		return op, nil
 	}
-	if err := op.SignIn(); err != nil {
+	if err := op.SignIn(ctx); err != nil {
 		return nil, err
 	}
 	return op, nil
 }
 
-func (o *OnePassClient) SignIn() error {
-	cmd := exec.Command(o.PathToOp, "signin", o.Subdomain, o.Email, o.SecretKey, "--output=raw")
-	stdin, err := cmd.StdinPipe()
+// signInAddress returns the host op signs in to. The address wins over the subdomain,
+// which only covers accounts hosted on 1password.com.
+func signInAddress(address, subdomain string) (string, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		if strings.Contains(subdomain, ".") {
+			address = subdomain
+		} else {
+			address = subdomain + ".1password.com"
+		}
+	}
+	raw := address
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || strings.Trim(u.Path, "/") != "" {
+		return "", fmt.Errorf("sign in address %s is not valid, use a host like company.1password.eu", address)
+	}
+	return strings.ToLower(u.Host), nil
+}
+
+// accountShorthand derives the default op shorthand from the first label of the address,
+// e.g. company for company.1password.eu
+func accountShorthand(address string) string {
+	host := address
+	if i := strings.IndexAny(host, ".:"); i > 0 {
+		host = host[:i]
+	}
+	return invalidEnvChars.ReplaceAllString(host, "_")
+}
+
+var invalidEnvChars = regexp.MustCompile("[^A-Za-z0-9_]")
+
+// sessionKeyName returns the environment variable op reads the session token from
+func sessionKeyName(account string) string {
+	return "OP_SESSION_" + invalidEnvChars.ReplaceAllString(account, "_")
+}
+
+// opConfigDir returns the op config directory of the account, so clients signed in to
+// different accounts never share op configuration, even for the same address.
+// The directory is stable to let op reuse its device registration between runs.
+func opConfigDir(account, address, email string) (string, error) {
+	config, err := os.UserConfigDir()
 	if err != nil {
-		return err
+		return "", fmt.Errorf("Could not find config dir for op client: %w", err)
 	}
-	go func() {
-		defer stdin.Close()
-		if _, err := io.WriteString(stdin, fmt.Sprintf("%s\n", o.Password)); err != nil {
-			log.Println("[ERROR] ", err)
-		}
-	}()
+	dir := filepath.Join(config, "terraform-provider-onepassword", account+"_"+fieldNumber(address + "\n" + email)[:8])
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("Could not create config dir for op client: %w", err)
+	}
+	return dir, nil
+}
 
+// SignIn signs in to the account and keeps the session token.
+// The secret key and the password are answered to the prompts of op on stdin,
+// arguments are visible to other local users.
+func (o *OnePassClient) SignIn(ctx context.Context) error {
+	args := []string{"signin", o.Address, o.Email, "--output=raw", "--shorthand=" + o.Account}
+	if o.ConfigDir != "" {
+		args = append(args, "--config="+o.ConfigDir)
+	}
+	cmd := o.execCommand(ctx, o.PathToOp, args...)
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("%s\n%s\n", o.SecretKey, o.Password))
+
+	start := time.Now()
 	session, err := cmd.CombinedOutput()
+	// signin output is the session token, it's never logged
+	tflog.Debug(ctx, "op signin finished",
+		"address", o.Address, "account", o.Account, "duration_ms", time.Since(start).Milliseconds(), "exit_code", exitCode(err))
 	if err != nil {
 		return errors.New(fmt.Sprintf("Cannot signin: %s\nExit code: %s", string(session), err))
 	}
@@ -311,36 +353,87 @@ func (o *OnePassClient) SignIn() error {
 	return nil
 }
 
-func (o *OnePassClient) RunSimpleCmd(args ...string) ([]byte, error) {
-	return o.RunConfigurableCmd(args, func(cmd *exec.Cmd) error { return nil })
+func (o *OnePassClient) RunSimpleCmd(ctx context.Context, args ...string) ([]byte, error) {
+	return o.RunConfigurableCmd(ctx, args, func(cmd *exec.Cmd) error { return nil })
 }
 
-func (o *OnePassClient) RunStdinCmd(b []byte, args ...string) ([]byte, error) {
-	return o.RunConfigurableCmd(args, func(cmd *exec.Cmd) error {
-		stdin, err := cmd.StdinPipe()
-		if err != nil {
-			return err
-		}
-		defer stdin.Close()
-		_, err = stdin.Write(b)
-		return err
+func (o *OnePassClient) RunStdinCmd(ctx context.Context, b []byte, args ...string) ([]byte, error) {
+	return o.RunConfigurableCmd(ctx, args, func(cmd *exec.Cmd) error {
+		cmd.Stdin = bytes.NewReader(b)
+		return nil
 	})
 }
 
-func (o *OnePassClient) RunConfigurableCmd(args []string, configureFunc func(*exec.Cmd) error) ([]byte, error) {
-	args = append(args, fmt.Sprintf("--session=%s", strings.Trim(o.Session, "\n")))
+// RunConfigurableCmd runs op and retries it with jittered exponential backoff
+// while it fails with rate limiting or, for idempotent subcommands, network errors.
+// RetryTimeout only limits the retries: no attempt is started after it has passed
+// since the first one, but a running op process is never killed because of it.
+// The op process is killed when ctx is cancelled or its deadline is exceeded.
+func (o *OnePassClient) RunConfigurableCmd(ctx context.Context, args []string, configureFunc func(*exec.Cmd) error) ([]byte, error) {
+	if o.ConfigDir != "" {
+		args = append(args, "--config="+o.ConfigDir)
+	}
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		res, err := o.runCmd(ctx, args, configureFunc)
+		if err != nil && ctx.Err() != nil {
+			return res, fmt.Errorf("op %s was interrupted: %w", args[0], ctx.Err())
+		}
+		if err == nil || attempt >= o.MaxRetries || !isRetryable(args, res) {
+			return res, err
+		}
+		delay := retryDelay(attempt)
+		if o.RetryTimeout > 0 && time.Since(start)+delay > o.RetryTimeout {
+			return res, err
+		}
+		tflog.Warn(ctx, "op command failed with a transient error, retrying",
+			"subcommand", args[0], "delay_ms", delay.Milliseconds(), "attempt", attempt+1, "max_retries", o.MaxRetries)
+		select {
+		case <-ctx.Done():
+			return res, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (o *OnePassClient) runCmd(ctx context.Context, args []string, configureFunc func(*exec.Cmd) error) ([]byte, error) {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
-	cmd := o.execCommand(o.PathToOp, args...)
+	cmd := o.execCommand(ctx, o.PathToOp, args...)
+	// the session is passed through the environment, arguments are visible to other local users
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", sessionKeyName(o.Account), strings.Trim(o.Session, "\n")))
 	err := configureFunc(cmd)
 	if err != nil {
 		return nil, err
 	}
-	return cmd.CombinedOutput()
+	stdin := 0
+	if r, ok := cmd.Stdin.(*bytes.Reader); ok {
+		stdin = r.Len()
+	}
+	start := time.Now()
+	res, err := cmd.CombinedOutput()
+	logCommand(ctx, args, stdin, res, time.Since(start), err)
+	return res, err
+}
+
+// retryDelay returns a random delay up to the exponential backoff of the attempt ("full jitter")
+func retryDelay(attempt int) time.Duration {
+	backoff := retryMaxDelay
+	if attempt < 16 {
+		if d := retryBaseDelay << uint(attempt); d < retryMaxDelay {
+			backoff = d
+		}
+	}
+	return time.Duration(rand.Int63n(int64(backoff)) + 1)
 }
 
 func prettyError(args []string, res []byte, err error) error {
-	return fmt.Errorf("some error in command %v\nError: %s\nOutput: %s", args[:len(args)-1], err, res)
+	return &CommandError{
+		Args:   args,
+		Output: res,
+		Err:    err,
+		Kind:   classifyError(res),
+	}
 }
 
 func getResultID(r []byte) (string, error) {
@@ -369,9 +462,9 @@ func getIDEmail(d *schema.ResourceData) string {
 	return d.Get("email").(string)
 }
 
-func (o *OnePassClient) Delete(resource string, id string) error {
+func (o *OnePassClient) Delete(ctx context.Context, resource string, id string) error {
 	args := []string{opPasswordDelete, resource, id}
-	res, err := o.RunSimpleCmd(args...)
+	res, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
 		return prettyError(args, res, err)
 	}
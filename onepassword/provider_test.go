@@ -1,9 +1,14 @@
 package onepassword
 
 import (
+	"context"
+	"errors"
+	"os"
 	"os/exec"
 	"strings"
 	"sync"
+	"testing"
+	"time"
 )
 
 type mockOnePassConfig struct {
@@ -18,16 +23,280 @@ func mockOnePassClient(params *mockOnePassConfig) *OnePassClient {
 	}
 
 	if params.runCmd != nil {
-		ret.execCommand = func(binary string, args ...string) *exec.Cmd {
+		ret.execCommand = func(ctx context.Context, binary string, args ...string) *exec.Cmd {
 			params.execCommandResults = append([]string{binary}, args...)
 
 			out, err := params.runCmd()
 			if err != nil {
-				return exec.Command("sh", "-c", "echo "+strings.ReplaceAll(err.Error(), `"`, `\"`)+" && false")
+				return exec.CommandContext(ctx, "sh", "-c", "echo "+strings.ReplaceAll(err.Error(), `"`, `\"`)+" && false")
 			}
-			return exec.Command("sh", "-c", "echo "+strings.ReplaceAll(out, `"`, `\"`))
+			return exec.CommandContext(ctx, "sh", "-c", "echo "+strings.ReplaceAll(out, `"`, `\"`))
 		}
 	}
 
 	return ret
 }
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOnePassClient_RunSimpleCmdRetries(t *testing.T) {
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = time.Second }()
+
+	tests := []struct {
+		name         string
+		args         []string
+		failures     int
+		output       string
+		maxRetries   int
+		retryTimeout time.Duration
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "rate limited then success",
+			args:         []string{"get", "item", "foo"},
+			failures:     2,
+			output:       "Too Many Requests",
+			maxRetries:   3,
+			wantAttempts: 3,
+		},
+		{
+			name:         "connection reset exceeds retries",
+			args:         []string{"get", "item", "foo"},
+			failures:     5,
+			output:       "read: connection reset by peer",
+			maxRetries:   2,
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "not retryable",
+			args:         []string{"get", "item", "foo"},
+			failures:     1,
+			output:       "oops",
+			maxRetries:   3,
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "create rate limited then success",
+			args:         []string{"create", "vault", "foo"},
+			failures:     1,
+			output:       "Too Many Requests",
+			maxRetries:   3,
+			wantAttempts: 2,
+		},
+		{
+			name:         "create not retried on timeout",
+			args:         []string{"create", "item", "login"},
+			failures:     1,
+			output:       "dial tcp: i/o timeout",
+			maxRetries:   3,
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "edit not retried on network error",
+			args:         []string{"edit", "group", "foo"},
+			failures:     1,
+			output:       "read: connection reset by peer",
+			maxRetries:   3,
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "delete retried on network error",
+			args:         []string{"delete", "item", "foo"},
+			failures:     1,
+			output:       "unexpected EOF",
+			maxRetries:   3,
+			wantAttempts: 2,
+		},
+		{
+			name:         "retry timeout exceeded",
+			args:         []string{"get", "item", "foo"},
+			failures:     5,
+			output:       "Too Many Requests",
+			maxRetries:   3,
+			retryTimeout: time.Nanosecond,
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			o := mockOnePassClient(&mockOnePassConfig{
+				runCmd: func() (string, error) {
+					attempts++
+					if attempts <= tt.failures {
+						return "", errors.New(tt.output)
+					}
+					return "ok", nil
+				},
+			})
+			o.MaxRetries = tt.maxRetries
+			o.RetryTimeout = tt.retryTimeout
+
+			_, err := o.RunSimpleCmd(context.Background(), tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("OnePassClient.RunSimpleCmd() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("OnePassClient.RunSimpleCmd() attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestOnePassClient_RunSimpleCmdCancelled(t *testing.T) {
+	o := mockOnePassClient(&mockOnePassConfig{
+		runCmd: func() (string, error) {
+			return "ok", nil
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := o.RunSimpleCmd(ctx, "get", "item", "foo"); !errors.Is(err, context.Canceled) {
+		t.Errorf("OnePassClient.RunSimpleCmd() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestOnePassClient_SignIn(t *testing.T) {
+	config := &mockOnePassConfig{
+		runCmd: func() (string, error) {
+			return "session-token", nil
+		},
+	}
+	o := mockOnePassClient(config)
+	o.Address = "company.1password.com"
+	o.Email = "admin@example.com"
+	o.SecretKey = "A3-XXXXXX-XXXXXX-XXXXX-XXXXX-XXXXX-XXXXX"
+	o.Password = "secret"
+	o.Account = "company"
+
+	if err := o.SignIn(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"op", "signin", "company.1password.com", "admin@example.com", "--output=raw", "--shorthand=company"}
+	if strings.Join(config.execCommandResults, " ") != strings.Join(want, " ") {
+		t.Errorf("OnePassClient.SignIn() args = %v, want %v", config.execCommandResults, want)
+	}
+	for _, arg := range config.execCommandResults {
+		if strings.Contains(arg, o.SecretKey) || strings.Contains(arg, o.Password) {
+			t.Errorf("OnePassClient.SignIn() passed a secret as argument: %v", config.execCommandResults)
+		}
+	}
+	if strings.TrimSpace(o.Session) != "session-token" {
+		t.Errorf("OnePassClient.SignIn() session = %q, want session-token", o.Session)
+	}
+}
+
+func Test_opConfigDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	prod, err := opConfigDir("prod", "company.1password.com", "admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	staging, err := opConfigDir("staging", "company.1password.com", "admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := opConfigDir("prod", "company.1password.com", "other@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := opConfigDir("prod", "company.1password.com", "admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if prod == staging || prod == other {
+		t.Errorf("opConfigDir() is shared between accounts: %s, %s, %s", prod, staging, other)
+	}
+	if prod != again {
+		t.Errorf("opConfigDir() is not stable: %s, %s", prod, again)
+	}
+	info, err := os.Stat(prod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("opConfigDir() mode = %v, want 0700", info.Mode().Perm())
+	}
+}
+
+func Test_signInAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		address     string
+		subdomain   string
+		want        string
+		wantAccount string
+		wantSession string
+		wantErr     bool
+	}{
+		{
+			name:        "subdomain",
+			subdomain:   "team-foo",
+			want:        "team-foo.1password.com",
+			wantAccount: "team_foo",
+			wantSession: "OP_SESSION_team_foo",
+		},
+		{
+			name:        "eu account",
+			address:     "company.1password.eu",
+			subdomain:   "my",
+			want:        "company.1password.eu",
+			wantAccount: "company",
+			wantSession: "OP_SESSION_company",
+		},
+		{
+			name:        "self hosted with scheme and port",
+			address:     "https://Vault.Example.com:8443/",
+			want:        "vault.example.com:8443",
+			wantAccount: "vault",
+			wantSession: "OP_SESSION_vault",
+		},
+		{
+			name:        "full domain as subdomain",
+			subdomain:   "company.1password.ca",
+			want:        "company.1password.ca",
+			wantAccount: "company",
+			wantSession: "OP_SESSION_company",
+		},
+		{
+			name:    "path",
+			address: "company.1password.eu/signin",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := signInAddress(tt.address, tt.subdomain)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("signInAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("signInAddress() = %s, want %s", got, tt.want)
+			}
+			account := accountShorthand(got)
+			if account != tt.wantAccount {
+				t.Errorf("accountShorthand() = %s, want %s", account, tt.wantAccount)
+			}
+			if key := sessionKeyName(account); key != tt.wantSession {
+				t.Errorf("sessionKeyName() = %s, want %s", key, tt.wantSession)
+			}
+		})
+	}
+}
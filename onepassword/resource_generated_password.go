@@ -0,0 +1,137 @@
+package onepassword
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceGeneratedPassword generates a password once and keeps it in the state only,
+// no item is created. Any change of the recipe or keepers generates a new password.
+func resourceGeneratedPassword() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceGeneratedPasswordRead,
+		CreateContext: resourceGeneratedPasswordCreate,
+		DeleteContext: resourceGeneratedPasswordDelete,
+		CustomizeDiff: resourceGeneratedPasswordCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"length": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          32,
+				ValidateDiagFunc: intBetweenDiag(1, 64),
+				Description:      "Number of characters, ignored for memorable passwords",
+			},
+			"letters": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Use lower and upper case letters",
+			},
+			"digits": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Use digits, memorable passwords get a digit after every word",
+			},
+			"symbols": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Use symbols, ignored for memorable passwords",
+			},
+			"words": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          0,
+				ValidateDiagFunc: intBetweenDiag(0, 15),
+				Description:      "Generate a memorable password of this many words of the EFF short wordlist instead of random characters",
+			},
+			"separator": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "-",
+				Description: "Separator between the words of memorable passwords",
+			},
+			"capitalize": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Capitalize the words of memorable passwords",
+			},
+			"keepers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values, a new password is generated when they change",
+			},
+			"result": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+// generatedPasswordRecipe builds the recipe from the attributes read with get
+func generatedPasswordRecipe(get func(string) interface{}) PasswordRecipe {
+	return PasswordRecipe{
+		Length:     get("length").(int),
+		Letters:    get("letters").(bool),
+		Digits:     get("digits").(bool),
+		Symbols:    get("symbols").(bool),
+		Words:      get("words").(int),
+		Separator:  get("separator").(string),
+		Capitalize: get("capitalize").(bool),
+	}
+}
+
+// resourceGeneratedPasswordCustomizeDiff rejects recipes no password can be generated from
+// at plan time, the length depends on the enabled classes so it can't be validated alone
+func resourceGeneratedPasswordCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, k := range []string{"length", "letters", "digits", "symbols", "words"} {
+		if !d.NewValueKnown(k) {
+			return nil
+		}
+	}
+	return generatedPasswordRecipe(d.Get).Validate()
+}
+
+func resourceGeneratedPasswordCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	password, err := generatedPasswordRecipe(d.Get).Generate()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the id is random, so it can't leak anything about the password
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(hex.EncodeToString(id))
+	if err := d.Set("result", password); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceGeneratedPasswordRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+func resourceGeneratedPasswordDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
@@ -3,6 +3,7 @@ package onepassword
 import (
 	"context"
 	"errors"
+	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -14,6 +15,12 @@ func resourceGroup() *schema.Resource {
 		CreateContext: resourceGroupCreate,
 		UpdateContext: resourceGroupUpdate,
 		DeleteContext: resourceGroupDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				if err := resourceGroupRead(ctx, d, meta); err.HasError() {
@@ -37,9 +44,16 @@ func resourceGroup() *schema.Resource {
 
 func resourceGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	m := meta.(*Meta)
-	v, err := m.onePassClient.ReadGroup(getID(d))
+	v, err := m.onePassClient.ReadGroup(ctx, getID(d))
 	if err != nil {
 		return diag.FromErr(err)
+	} else if v == nil {
+		if d.Id() == "" {
+			return diag.Errorf("group %s not found", getID(d))
+		}
+		log.Printf("[INFO] Group %s not found", getID(d))
+		d.SetId("")
+		return nil
 	} else if v.State == GroupStateDeleted {
 		d.SetId("")
 		return nil
@@ -59,7 +73,7 @@ func resourceGroupRead(ctx context.Context, d *schema.ResourceData, meta interfa
 
 func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	m := meta.(*Meta)
-	_, err := m.onePassClient.CreateGroup(&Group{
+	_, err := m.onePassClient.CreateGroup(ctx, &Group{
 		Name: d.Get("name").(string),
 	})
 	if err != nil {
@@ -70,7 +84,7 @@ func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, meta inter
 
 func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	m := meta.(*Meta)
-	err := m.onePassClient.DeleteGroup(getID(d))
+	err := m.onePassClient.DeleteGroup(ctx, getID(d))
 	if err == nil {
 		d.SetId("")
 		return nil
@@ -85,7 +99,7 @@ func resourceGroupUpdate(ctx context.Context, d *schema.ResourceData, meta inter
 		Name: d.Get("name").(string),
 	}
 
-	if err := m.onePassClient.UpdateGroup(getID(d), g); err != nil {
+	if err := m.onePassClient.UpdateGroup(ctx, getID(d), g); err != nil {
 		return diag.FromErr(err)
 	}
 	return resourceGroupRead(ctx, d, meta)
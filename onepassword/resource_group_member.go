@@ -2,7 +2,9 @@ package onepassword
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -14,8 +16,13 @@ func resourceGroupMember() *schema.Resource {
 		ReadContext:   resourceGroupMemberRead,
 		CreateContext: resourceGroupMemberCreate,
 		DeleteContext: resourceGroupMemberDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceGroupMemberImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"group": {
@@ -33,20 +40,25 @@ func resourceGroupMember() *schema.Resource {
 }
 
 func resourceGroupMemberRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	groupID, userID, err := resourceGroupMemberExtractID(d.Id())
+	groupID, userID, err := resourceGroupMemberIDs(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	m := meta.(*Meta)
-	v, err := m.onePassClient.ListGroupMembers(groupID)
-	if err != nil {
+	v, err := m.onePassClient.ListGroupMembers(ctx, groupID)
+	if errors.Is(err, ErrNotFound) {
+		log.Printf("[INFO] Group %s not found", groupID)
+		d.SetId("")
+		return nil
+	} else if err != nil {
 		return diag.FromErr(err)
 	}
 
 	var found string
 	for _, member := range v {
-		if member.UUID == userID {
+		// op prints user UUIDs in upper case while the resource ID keeps them in lower case
+		if strings.EqualFold(member.UUID, userID) {
 			found = member.UUID
 		}
 	}
@@ -65,6 +77,7 @@ func resourceGroupMemberRead(ctx context.Context, d *schema.ResourceData, meta i
 func resourceGroupMemberCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	m := meta.(*Meta)
 	err := m.onePassClient.CreateGroupMember(
+		ctx,
 		d.Get("group").(string),
 		d.Get("user").(string),
 	)
@@ -77,13 +90,14 @@ func resourceGroupMemberCreate(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceGroupMemberDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	groupID, userID, err := resourceGroupMemberExtractID(d.Id())
+	groupID, userID, err := resourceGroupMemberIDs(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	m := meta.(*Meta)
 	err = m.onePassClient.DeleteGroupMember(
+		ctx,
 		groupID,
 		userID,
 	)
@@ -95,22 +109,73 @@ func resourceGroupMemberDelete(ctx context.Context, d *schema.ResourceData, meta
 	return nil
 }
 
+// resourceGroupMemberImport accepts either the "groupid-userid" resource ID
+// or "GroupName/user@example.com" which is resolved to the group and user UUIDs
+func resourceGroupMemberImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	i := strings.LastIndex(d.Id(), "/")
+	if i < 0 {
+		if _, _, err := resourceGroupMemberExtractID(d.Id()); err != nil {
+			return nil, err
+		}
+		return []*schema.ResourceData{d}, nil
+	}
+
+	m := meta.(*Meta)
+	groupName, email := d.Id()[:i], d.Id()[i+1:]
+	group, err := m.onePassClient.ReadGroup(ctx, groupName)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("Group %s not found", groupName)
+	}
+	user, err := m.onePassClient.ReadUser(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("User %s not found", email)
+	}
+
+	d.SetId(resourceGroupMemberBuildID(group.UUID, user.UUID))
+	d.Set("group", group.UUID)
+	d.Set("user", user.UUID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceGroupMemberIDs returns the group and user UUIDs of the resource.
+// The UUIDs kept in state are preferred as they preserve the casing op uses,
+// the resource ID is only parsed right after import.
+func resourceGroupMemberIDs(d *schema.ResourceData) (groupID, userID string, err error) {
+	groupID, userID, err = resourceGroupMemberExtractID(d.Id())
+	if err != nil {
+		return "", "", err
+	}
+	if group := d.Get("group").(string); strings.EqualFold(group, groupID) {
+		groupID = group
+	}
+	if user := d.Get("user").(string); strings.EqualFold(user, userID) {
+		userID = user
+	}
+	return groupID, userID, nil
+}
+
 // resourceGroupMemberBuildID will conjoin the group ID and user ID into a single string
 // This is used as the resource ID.
 //
-// Note that user ID is being lowercased. Some operations require this user ID to be uppercased.
-// Use the resourceGroupMemberExtractID function to correctly reverse this encoding.
+// Note that both IDs are being lowercased, use resourceGroupMemberIDs to get them
+// with the casing op expects.
 func resourceGroupMemberBuildID(groupID, userID string) string {
 	return strings.ToLower(groupID + "-" + strings.ToLower(userID))
 }
 
 // resourceGroupMemberExtractID will split the group ID and user ID from a given resource ID
 //
-// Note that user ID is being uppercased. Some operations require this user ID to be uppercased.
+// Note that the IDs keep the casing of the resource ID, compare them case-insensitively.
 func resourceGroupMemberExtractID(id string) (groupID, userID string, err error) {
 	spl := strings.Split(id, "-")
-	if len(spl) != 2 {
-		return "", "", fmt.Errorf("Improperly formatted group member string. The format \"groupid-userid\" is expected")
+	if len(spl) != 2 || spl[0] == "" || spl[1] == "" {
+		return "", "", fmt.Errorf("Improperly formatted group member string. The format \"groupid-userid\" or \"GroupName/user@example.com\" is expected")
 	}
-	return spl[0], strings.ToUpper(spl[1]), nil
+	return spl[0], spl[1], nil
 }
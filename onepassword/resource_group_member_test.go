@@ -1,6 +1,12 @@
 package onepassword
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
 
 func Test_resourceGroupMemberBuildID(t *testing.T) {
 	want := "v3zk6wiptl42r7cmzbmf23unny-tgkw5a3cpbcu5end3lld3wckxi"
@@ -12,21 +18,138 @@ func Test_resourceGroupMemberBuildID(t *testing.T) {
 }
 
 func Test_resourceGroupMemberExtractID(t *testing.T) {
-	wantGroup := "v3zk6wiptl42r7cmzbmf23unny"
-	wantUser := "TGKW5A3CPBCU5END3LLD3WCKXI"
-	gotGroup, gotUser, err := resourceGroupMemberExtractID("v3zk6wiptl42r7cmzbmf23unny-tgkw5a3cpbcu5end3lld3wckxi")
-
-	if err != nil {
-		t.Error(err)
-	} else if wantGroup != gotGroup {
-		t.Error("Did not correctly extract the group ID: " + gotGroup)
-	} else if wantUser != gotUser {
-		t.Error("Did not correctly extract the user ID: " + gotUser)
-	}
-
-	// Test malformed ID
-	_, _, err = resourceGroupMemberExtractID("totally not the right id")
-	if err == nil {
-		t.Error("Error was not returned from malformed id")
+	tests := []struct {
+		name      string
+		id        string
+		wantGroup string
+		wantUser  string
+		wantErr   bool
+	}{
+		{
+			name:      "lower case",
+			id:        "v3zk6wiptl42r7cmzbmf23unny-tgkw5a3cpbcu5end3lld3wckxi",
+			wantGroup: "v3zk6wiptl42r7cmzbmf23unny",
+			wantUser:  "tgkw5a3cpbcu5end3lld3wckxi",
+		},
+		{
+			name:      "casing is kept",
+			id:        "V3ZK6WIPTL42R7CMZBMF23UNNY-TGKW5A3CPBCU5END3LLD3WCKXI",
+			wantGroup: "V3ZK6WIPTL42R7CMZBMF23UNNY",
+			wantUser:  "TGKW5A3CPBCU5END3LLD3WCKXI",
+		},
+		{
+			name:    "malformed",
+			id:      "totally not the right id",
+			wantErr: true,
+		},
+		{
+			name:    "missing user",
+			id:      "v3zk6wiptl42r7cmzbmf23unny-",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotGroup, gotUser, err := resourceGroupMemberExtractID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resourceGroupMemberExtractID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotGroup != tt.wantGroup {
+				t.Errorf("resourceGroupMemberExtractID() group = %s, want %s", gotGroup, tt.wantGroup)
+			}
+			if gotUser != tt.wantUser {
+				t.Errorf("resourceGroupMemberExtractID() user = %s, want %s", gotUser, tt.wantUser)
+			}
+		})
+	}
+}
+
+func Test_resourceGroupMemberImport(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		wantID    string
+		wantGroup string
+		wantUser  string
+		wantErr   bool
+	}{
+		{
+			name:   "resource id",
+			id:     "gdpvdudxrico74msloimk7qjna-fxa4ytulanfsrmt5qwdxvnuroe",
+			wantID: "gdpvdudxrico74msloimk7qjna-fxa4ytulanfsrmt5qwdxvnuroe",
+		},
+		{
+			name:      "group name and email",
+			id:        "Team-Ops/john.smith@example.com",
+			wantID:    "gdpvdudxrico74msloimk7qjna-fxa4ytulanfsrmt5qwdxvnuroe",
+			wantGroup: "gdpvdudxrico74msloimk7qjna",
+			wantUser:  "FXA4YTULANFSRMT5QWDXVNUROE",
+		},
+		{
+			name:    "unknown user",
+			id:      "Team-Ops/nobody@example.com",
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			id:      "foo",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &mockOnePassConfig{}
+			config.runCmd = func() (string, error) {
+				args := config.execCommandResults
+				switch {
+				case args[2] == GroupResource:
+					return `{ "uuid": "gdpvdudxrico74msloimk7qjna", "name": "Team-Ops" }`, nil
+				case args[3] == "john.smith@example.com":
+					return `{ "uuid": "FXA4YTULANFSRMT5QWDXVNUROE", "email": "john.smith@example.com" }`, nil
+				}
+				return "", fmt.Errorf("The requested resource was not found")
+			}
+			m := &Meta{onePassClient: mockOnePassClient(config)}
+			d := schema.TestResourceDataRaw(t, resourceGroupMember().Schema, map[string]interface{}{})
+			d.SetId(tt.id)
+
+			got, err := resourceGroupMemberImport(context.Background(), d, m)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resourceGroupMemberImport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got[0].Id() != tt.wantID {
+				t.Errorf("resourceGroupMemberImport() id = %s, want %s", got[0].Id(), tt.wantID)
+			}
+			if group := got[0].Get("group").(string); group != tt.wantGroup {
+				t.Errorf("resourceGroupMemberImport() group = %s, want %s", group, tt.wantGroup)
+			}
+			if user := got[0].Get("user").(string); user != tt.wantUser {
+				t.Errorf("resourceGroupMemberImport() user = %s, want %s", user, tt.wantUser)
+			}
+		})
+	}
+}
+
+func Test_resourceGroupMemberRead(t *testing.T) {
+	config := &mockOnePassConfig{
+		runCmd: func() (string, error) {
+			return `[{ "uuid": "FXA4YTULANFSRMT5QWDXVNUROE" }]`, nil
+		},
+	}
+	m := &Meta{onePassClient: mockOnePassClient(config)}
+	d := schema.TestResourceDataRaw(t, resourceGroupMember().Schema, map[string]interface{}{})
+	d.SetId("gdpvdudxrico74msloimk7qjna-fxa4ytulanfsrmt5qwdxvnuroe")
+
+	if diags := resourceGroupMemberRead(context.Background(), d, m); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if d.Id() == "" {
+		t.Fatal("resourceGroupMemberRead() removed an existing member")
+	}
+	if user := d.Get("user").(string); user != "FXA4YTULANFSRMT5QWDXVNUROE" {
+		t.Errorf("resourceGroupMemberRead() user = %s, want the UUID as printed by op", user)
 	}
 }
@@ -2,25 +2,49 @@ package onepassword
 
 import (
 	"context"
-	"errors"
-	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// commonCategories are the categories without a resource type of their own
+var commonCategories = []Category{
+	DatabaseCategory,
+	MembershipCategory,
+	WirelessRouterCategory,
+	DriverLicenseCategory,
+	OutdoorLicenseCategory,
+	PassportCategory,
+	EmailAccountCategory,
+	RewardProgramCategory,
+	SocialSecurityNumberCategory,
+	BankAccountCategory,
+	ServerCategory,
+}
+
+func commonCategoryNames() []string {
+	names := make([]string, 0, len(commonCategories))
+	for _, c := range commonCategories {
+		names = append(names, string(c))
+	}
+	return names
+}
+
 func resourceItemCommon() *schema.Resource {
 	return &schema.Resource{
 		ReadContext:   resourceItemCommonRead,
 		CreateContext: resourceItemCommonCreate,
+		UpdateContext: resourceItemUpdate,
+		CustomizeDiff: resourceItemCustomizeDiff,
 		DeleteContext: resourceItemDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 		Importer: &schema.ResourceImporter{
-			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				if err := resourceItemCommonRead(ctx, d, meta); err.HasError() {
-					return []*schema.ResourceData{d}, errors.New(err[0].Summary)
-				}
-				return []*schema.ResourceData{d}, nil
-			},
+			StateContext: resourceItemImporter(resourceItemCommonRead, commonCategories...),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -34,22 +58,10 @@ func resourceItemCommon() *schema.Resource {
 				ForceNew: true,
 			},
 			"template": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				ValidateDiagFunc: stringInSliceDiag([]string{
-					string(DatabaseCategory),
-					string(MembershipCategory),
-					string(WirelessRouterCategory),
-					string(DriverLicenseCategory),
-					string(OutdoorLicenseCategory),
-					string(PassportCategory),
-					string(EmailAccountCategory),
-					string(RewardProgramCategory),
-					string(SocialSecurityNumberCategory),
-					string(BankAccountCategory),
-					string(ServerCategory),
-				}, false),
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: stringInSliceDiag(commonCategoryNames(), false),
 			},
 			"tags": {
 				Type:     schema.TypeList,
@@ -63,34 +75,36 @@ func resourceItemCommon() *schema.Resource {
 				ForceNew: true,
 			},
 			"section": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				ForceNew: true,
+				Set:      sectionHash,
 				Elem:     sectionSchema(),
 			},
 			"archived": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
-				ForceNew: true,
+			},
+			"archive_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 		},
 	}
 }
 
 func resourceItemCommonRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	m := meta.(*Meta)
-	vaultID := d.Get("vault").(string)
-	v, err := m.onePassClient.ReadItem(getID(d), vaultID)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	if v == nil {
-		log.Printf("[INFO] Item %s not found in %s vault", getID(d), vaultID)
-		d.SetId("")
-		return nil
-	}
+	return readItem(ctx, d, meta, resourceItemCommonFlatten)
+}
 
+func resourceItemCommonFlatten(ctx context.Context, d *schema.ResourceData, meta interface{}, v *Item) diag.Diagnostics {
 	d.SetId(v.UUID)
 	if err := d.Set("name", v.Overview.Title); err != nil {
 		return diag.FromErr(err)
@@ -120,6 +134,7 @@ func resourceItemCommonCreate(ctx context.Context, d *schema.ResourceData, meta
 	item := &Item{
 		Vault:    d.Get("vault").(string),
 		Template: Category2Template(Category(d.Get("template").(string))),
+		Trashed:  ParseTrashed(d),
 		Overview: Overview{
 			Title: d.Get("name").(string),
 			Tags:  ParseTags(d),
@@ -130,10 +145,13 @@ func resourceItemCommonCreate(ctx context.Context, d *schema.ResourceData, meta
 		},
 	}
 	m := meta.(*Meta)
-	err := m.onePassClient.CreateItem(item)
+	err := m.onePassClient.CreateItem(ctx, item)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(item.UUID)
+	if diags := archiveCreatedItem(ctx, d, meta); diags.HasError() {
+		return diags
+	}
 	return resourceItemCommonRead(ctx, d, meta)
 }
@@ -2,8 +2,7 @@ package onepassword
 
 import (
 	"context"
-	"errors"
-	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -13,14 +12,17 @@ func resourceItemCreditCard() *schema.Resource {
 	return &schema.Resource{
 		ReadContext:   resourceItemCreditCardRead,
 		CreateContext: resourceItemCreditCardCreate,
+		UpdateContext: resourceItemUpdate,
+		CustomizeDiff: resourceItemCustomizeDiff,
 		DeleteContext: resourceItemDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 		Importer: &schema.ResourceImporter{
-			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				if err := resourceItemCreditCardRead(ctx, d, meta); err.HasError() {
-					return []*schema.ResourceData{d}, errors.New(err[0].Summary)
-				}
-				return []*schema.ResourceData{d}, nil
-			},
+			StateContext: resourceItemImporter(resourceItemCreditCardRead, CreditCardCategory),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -61,28 +63,21 @@ func resourceItemCreditCard() *schema.Resource {
 							Optional: true,
 							ForceNew: true,
 						},
+						// the detected type is unknown in the plan when the number changes,
+						// as replacing the item diffs every computed attribute again
 						"type": {
-							Type:     schema.TypeString,
-							Optional: true,
-							ForceNew: true,
-							ValidateDiagFunc: stringInSliceDiag([]string{
-								"mc",
-								"visa",
-								"amex",
-								"diners",
-								"carteblanche",
-								"discover",
-								"jcb",
-								"maestro",
-								"visaelectron",
-								"laser",
-								"unionpay",
-							}, true),
+							Type:             schema.TypeString,
+							Optional:         true,
+							Computed:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: stringInSliceDiag(cardTypes, true),
 						},
 						"number": {
-							Type:     schema.TypeString,
-							Optional: true,
-							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							Sensitive:        true,
+							ValidateDiagFunc: cardNumberValidateDiag(),
 						},
 						"cvv": {
 							Type:      schema.TypeString,
@@ -91,50 +86,75 @@ func resourceItemCreditCard() *schema.Resource {
 							Sensitive: true,
 						},
 						"expiry_date": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							StateFunc:        normalizeMonthYear,
+							ValidateDiagFunc: monthYearValidateDiag(),
+						},
+						"expiry_date_raw": {
 							Type:     schema.TypeInt,
-							Optional: true,
-							ForceNew: true,
+							Computed: true,
 						},
 						"valid_from": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							StateFunc:        normalizeMonthYear,
+							ValidateDiagFunc: monthYearValidateDiag(),
+						},
+						"valid_from_raw": {
 							Type:     schema.TypeInt,
-							Optional: true,
-							ForceNew: true,
+							Computed: true,
 						},
 						"field": sectionSchema().Schema["field"],
 					},
 				},
 			},
+			"last4": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Last four digits of the card number",
+			},
+			"expired": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the expiry date has passed",
+			},
 			"section": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				ForceNew: true,
+				Set:      sectionHash,
 				Elem:     sectionSchema(),
 			},
 			"archived": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
-				ForceNew: true,
+			},
+			"archive_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 		},
 	}
 }
 
 func resourceItemCreditCardRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	m := meta.(*Meta)
-	vaultID := d.Get("vault").(string)
-	v, err := m.onePassClient.ReadItem(getID(d), vaultID)
-	if err != nil {
+	return readItem(ctx, d, meta, resourceItemCreditCardFlatten)
+}
+
+func resourceItemCreditCardFlatten(ctx context.Context, d *schema.ResourceData, meta interface{}, v *Item) diag.Diagnostics {
+	if err := checkItemCategory(v, CreditCardCategory); err != nil {
 		return diag.FromErr(err)
 	}
-	if v == nil {
-		log.Printf("[INFO] Item %s not found in %s vault", getID(d), vaultID)
-		d.SetId("")
-		return nil
-	}
-	if v.Template != Category2Template(CreditCardCategory) {
-		return diag.FromErr(errors.New("item is not from " + string(CreditCardCategory)))
-	}
 
 	d.SetId(v.UUID)
 	if err := d.Set("name", v.Overview.Title); err != nil {
@@ -165,6 +185,13 @@ func resourceItemCreditCardRead(ctx context.Context, d *schema.ResourceData, met
 	}); err != nil {
 		return diag.FromErr(err)
 	}
+	number, expiry := cardDetails(v.Details.Sections)
+	if err := d.Set("last4", cardLast4(number)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("expired", cardExpired(expiry, time.Now())); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("archived", v.Trashed == IsTrashed); err != nil {
 		diag.FromErr(err)
 	}
@@ -173,9 +200,14 @@ func resourceItemCreditCardRead(ctx context.Context, d *schema.ResourceData, met
 
 func resourceItemCreditCardCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	main := d.Get("main").([]interface{})[0].(map[string]interface{})
+	cardType := main["type"].(string)
+	if cardType == "" {
+		cardType = detectCardType(cardNumberDigits(main["number"].(string)))
+	}
 	item := &Item{
 		Vault:    d.Get("vault").(string),
 		Template: Category2Template(CreditCardCategory),
+		Trashed:  ParseTrashed(d),
 		Details: Details{
 			Notes: d.Get("notes").(string),
 			Sections: append(
@@ -200,7 +232,7 @@ func resourceItemCreditCardCreate(ctx context.Context, d *schema.ResourceData, m
 							{
 								Type:  "cctype",
 								Text:  "type",
-								Value: main["type"].(string),
+								Value: cardType,
 								N:     "type",
 								A: Annotation{
 									guarded: "yes",
@@ -235,7 +267,7 @@ func resourceItemCreditCardCreate(ctx context.Context, d *schema.ResourceData, m
 							{
 								Type:  "monthYear",
 								Text:  "expiry date",
-								Value: main["expiry_date"].(int),
+								Value: monthYearValue(main["expiry_date"]),
 								N:     "expiry",
 								A: Annotation{
 									guarded: "yes",
@@ -244,7 +276,7 @@ func resourceItemCreditCardCreate(ctx context.Context, d *schema.ResourceData, m
 							{
 								Type:  "monthYear",
 								Text:  "valid from",
-								Value: main["valid_from"].(int),
+								Value: monthYearValue(main["valid_from"]),
 								N:     "validFrom",
 								A: Annotation{
 									guarded: "yes",
@@ -262,10 +294,13 @@ func resourceItemCreditCardCreate(ctx context.Context, d *schema.ResourceData, m
 		},
 	}
 	m := meta.(*Meta)
-	err := m.onePassClient.CreateItem(item)
+	err := m.onePassClient.CreateItem(ctx, item)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(item.UUID)
+	if diags := archiveCreatedItem(ctx, d, meta); diags.HasError() {
+		return diags
+	}
 	return resourceItemCreditCardRead(ctx, d, meta)
 }
@@ -3,9 +3,7 @@ package onepassword
 import (
 	"context"
 	b64 "encoding/base64"
-	"errors"
 	"io/ioutil"
-	"log"
 	"path/filepath"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -16,14 +14,17 @@ func resourceItemDocument() *schema.Resource {
 	return &schema.Resource{
 		ReadContext:   resourceItemDocumentRead,
 		CreateContext: resourceItemDocumentCreate,
+		UpdateContext: resourceItemUpdate,
+		CustomizeDiff: resourceItemCustomizeDiff,
 		DeleteContext: resourceItemDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 		Importer: &schema.ResourceImporter{
-			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				if err := resourceItemDocumentRead(ctx, d, meta); err.HasError() {
-					return []*schema.ResourceData{d}, errors.New(err[0].Summary)
-				}
-				return []*schema.ResourceData{d}, nil
-			},
+			StateContext: resourceItemImporter(resourceItemDocumentRead, DocumentCategory),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -77,28 +78,30 @@ func resourceItemDocument() *schema.Resource {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
-				ForceNew: true,
+			},
+			"archive_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 		},
 	}
 }
 
 func resourceItemDocumentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return readItem(ctx, d, meta, resourceItemDocumentFlatten)
+}
+
+func resourceItemDocumentFlatten(ctx context.Context, d *schema.ResourceData, meta interface{}, v *Item) diag.Diagnostics {
 	m := meta.(*Meta)
-	vaultID := d.Get("vault").(string)
-	v, err := m.onePassClient.ReadItem(getID(d), vaultID)
-	if err != nil {
+	if err := checkItemCategory(v, DocumentCategory); err != nil {
 		return diag.FromErr(err)
 	}
-	if v == nil {
-		log.Printf("[INFO] Item %s not found in %s vault", getID(d), vaultID)
-		d.SetId("")
-		return nil
-	}
-
-	if v.Template != Category2Template(DocumentCategory) {
-		return diag.FromErr(errors.New("item is not from " + string(DocumentCategory)))
-	}
 
 	d.SetId(v.UUID)
 	if err := d.Set("name", v.Overview.Title); err != nil {
@@ -117,7 +120,7 @@ func resourceItemDocumentRead(ctx context.Context, d *schema.ResourceData, meta
 		diag.FromErr(err)
 	}
 
-	content, err := m.onePassClient.ReadDocument(v.UUID)
+	content, err := m.onePassClient.ReadDocument(ctx, v.UUID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -156,6 +159,7 @@ func resourceItemDocumentCreate(ctx context.Context, d *schema.ResourceData, met
 	item := &Item{
 		Vault:    d.Get("vault").(string),
 		Template: Category2Template(DocumentCategory),
+		Trashed:  ParseTrashed(d),
 		Overview: Overview{
 			Title: d.Get("name").(string),
 			Tags:  ParseTags(d),
@@ -167,10 +171,13 @@ func resourceItemDocumentCreate(ctx context.Context, d *schema.ResourceData, met
 		},
 	}
 	m := meta.(*Meta)
-	err := m.onePassClient.CreateDocument(item, fileContent)
+	err := m.onePassClient.CreateDocument(ctx, item, fileContent)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(item.UUID)
+	if diags := archiveCreatedItem(ctx, d, meta); diags.HasError() {
+		return diags
+	}
 	return resourceItemDocumentRead(ctx, d, meta)
 }
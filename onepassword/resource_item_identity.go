@@ -2,8 +2,6 @@ package onepassword
 
 import (
 	"context"
-	"errors"
-	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -16,14 +14,17 @@ func resourceItemIdentity() *schema.Resource {
 	return &schema.Resource{
 		ReadContext:   resourceItemIdentityRead,
 		CreateContext: resourceItemIdentityCreate,
+		UpdateContext: resourceItemUpdate,
+		CustomizeDiff: resourceItemCustomizeDiff,
 		DeleteContext: resourceItemDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 		Importer: &schema.ResourceImporter{
-			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				if err := resourceItemIdentityRead(ctx, d, meta); err.HasError() {
-					return []*schema.ResourceData{d}, errors.New(err[0].Summary)
-				}
-				return []*schema.ResourceData{d}, nil
-			},
+			StateContext: resourceItemImporter(resourceItemIdentityRead, IdentityCategory),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -82,9 +83,15 @@ func resourceItemIdentity() *schema.Resource {
 							ValidateDiagFunc: stringInSliceDiag([]string{"male", "female"}, true),
 						},
 						"birth_date": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							StateFunc:        normalizeDate,
+							ValidateDiagFunc: dateValidateDiag(),
+						},
+						"birth_date_raw": {
 							Type:     schema.TypeInt,
-							Optional: true,
-							ForceNew: true,
+							Computed: true,
 						},
 						"occupation": {
 							Type:     schema.TypeString,
@@ -177,36 +184,39 @@ func resourceItemIdentity() *schema.Resource {
 				},
 			},
 			"section": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				ForceNew: true,
+				Set:      sectionHash,
 				Elem:     sectionSchema(),
 			},
 			"archived": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
-				ForceNew: true,
+			},
+			"archive_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 		},
 	}
 }
 
 func resourceItemIdentityRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	m := meta.(*Meta)
-	vaultID := d.Get("vault").(string)
-	v, err := m.onePassClient.ReadItem(getID(d), vaultID)
-	if err != nil {
+	return readItem(ctx, d, meta, resourceItemIdentityFlatten)
+}
+
+func resourceItemIdentityFlatten(ctx context.Context, d *schema.ResourceData, meta interface{}, v *Item) diag.Diagnostics {
+	if err := checkItemCategory(v, IdentityCategory); err != nil {
 		return diag.FromErr(err)
 	}
-	if v == nil {
-		log.Printf("[INFO] Item %s not found in %s vault", getID(d), vaultID)
-		d.SetId("")
-		return nil
-	}
-	if v.Template != Category2Template(IdentityCategory) {
-		return diag.FromErr(errors.New("item is not from " + string(IdentityCategory)))
-	}
 
 	d.SetId(v.UUID)
 	if err := d.Set("name", v.Overview.Title); err != nil {
@@ -272,6 +282,7 @@ func resourceItemIdentityCreate(ctx context.Context, d *schema.ResourceData, met
 	item := &Item{
 		Vault:    d.Get("vault").(string),
 		Template: Category2Template(IdentityCategory),
+		Trashed:  ParseTrashed(d),
 		Details: Details{
 			Notes: d.Get("notes").(string),
 			Sections: append(
@@ -328,7 +339,7 @@ func resourceItemIdentityCreate(ctx context.Context, d *schema.ResourceData, met
 							{
 								Type:  "date",
 								Text:  "birth date",
-								Value: main["birth_date"].(int),
+								Value: dateValue(main["birth_date"]),
 								N:     "birthdate",
 								A: Annotation{
 									guarded: "yes",
@@ -391,7 +402,7 @@ func resourceItemIdentityCreate(ctx context.Context, d *schema.ResourceData, met
 							{
 								Type:  "address",
 								Text:  "address",
-								Value: address["address"].(map[string]interface{}),
+								Value: ParseAddress(address["address"]),
 								N:     "address",
 								A: Annotation{
 									guarded: "yes",
@@ -478,10 +489,13 @@ func resourceItemIdentityCreate(ctx context.Context, d *schema.ResourceData, met
 		},
 	}
 	m := meta.(*Meta)
-	err := m.onePassClient.CreateItem(item)
+	err := m.onePassClient.CreateItem(ctx, item)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(item.UUID)
+	if diags := archiveCreatedItem(ctx, d, meta); diags.HasError() {
+		return diags
+	}
 	return resourceItemIdentityRead(ctx, d, meta)
 }
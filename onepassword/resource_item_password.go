@@ -2,8 +2,6 @@ package onepassword
 
 import (
 	"context"
-	"errors"
-	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -13,14 +11,17 @@ func resourceItemPassword() *schema.Resource {
 	return &schema.Resource{
 		ReadContext:   resourceItemPasswordRead,
 		CreateContext: resourceItemPasswordCreate,
+		UpdateContext: resourceItemUpdate,
+		CustomizeDiff: resourceItemCustomizeDiff,
 		DeleteContext: resourceItemDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 		Importer: &schema.ResourceImporter{
-			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				if err := resourceItemPasswordRead(ctx, d, meta); err.HasError() {
-					return []*schema.ResourceData{d}, errors.New(err[0].Summary)
-				}
-				return []*schema.ResourceData{d}, nil
-			},
+			StateContext: resourceItemImporter(resourceItemPasswordRead, PasswordCategory),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -51,41 +52,54 @@ func resourceItemPassword() *schema.Resource {
 				ForceNew: true,
 			},
 			"section": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				ForceNew: true,
+				Set:      sectionHash,
 				Elem:     sectionSchema(),
 			},
 			"url": {
 				Type:             schema.TypeString,
 				Optional:         true,
+				Computed:         true,
 				ForceNew:         true,
 				ValidateDiagFunc: urlValidateDiag(),
+				ConflictsWith:    []string{"urls"},
+			},
+			"urls": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				Elem:          urlsSchema(),
+				ConflictsWith: []string{"url"},
 			},
 			"archived": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
-				ForceNew: true,
+			},
+			"archive_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 		},
 	}
 }
 
 func resourceItemPasswordRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	m := meta.(*Meta)
-	vaultID := d.Get("vault").(string)
-	v, err := m.onePassClient.ReadItem(getID(d), vaultID)
-	if err != nil {
-		diag.FromErr(err)
-	}
-	if v == nil {
-		log.Printf("[INFO] Item %s not found in %s vault", getID(d), vaultID)
-		d.SetId("")
-		return nil
-	}
-	if v.Template != Category2Template(PasswordCategory) {
-		diag.FromErr(errors.New("item is not from " + string(PasswordCategory)))
+	return readItem(ctx, d, meta, resourceItemPasswordFlatten)
+}
+
+func resourceItemPasswordFlatten(ctx context.Context, d *schema.ResourceData, meta interface{}, v *Item) diag.Diagnostics {
+	if err := checkItemCategory(v, PasswordCategory); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(v.UUID)
@@ -95,6 +109,9 @@ func resourceItemPasswordRead(ctx context.Context, d *schema.ResourceData, meta
 	if err := d.Set("url", v.Overview.URL); err != nil {
 		diag.FromErr(err)
 	}
+	if err := d.Set("urls", ProcessURLs(v.Overview)); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("notes", v.Details.Notes); err != nil {
 		diag.FromErr(err)
 	}
@@ -117,12 +134,18 @@ func resourceItemPasswordRead(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceItemPasswordCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	urls, primaryURL, err := ParseURLs(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	item := &Item{
 		Vault:    d.Get("vault").(string),
 		Template: Category2Template(PasswordCategory),
+		Trashed:  ParseTrashed(d),
 		Overview: Overview{
 			Title: d.Get("name").(string),
-			URL:   d.Get("url").(string),
+			URL:   primaryURL,
+			URLs:  urls,
 			Tags:  ParseTags(d),
 		},
 		Details: Details{
@@ -132,10 +155,12 @@ func resourceItemPasswordCreate(ctx context.Context, d *schema.ResourceData, met
 		},
 	}
 	m := meta.(*Meta)
-	err := m.onePassClient.CreateItem(item)
-	if err != nil {
+	if err := m.onePassClient.CreateItem(ctx, item); err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(item.UUID)
+	if diags := archiveCreatedItem(ctx, d, meta); diags.HasError() {
+		return diags
+	}
 	return resourceItemPasswordRead(ctx, d, meta)
 }
@@ -2,8 +2,6 @@ package onepassword
 
 import (
 	"context"
-	"errors"
-	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -13,14 +11,17 @@ func resourceItemSoftwareLicense() *schema.Resource {
 	return &schema.Resource{
 		ReadContext:   resourceItemSoftwareLicenseRead,
 		CreateContext: resourceItemSoftwareLicenseCreate,
+		UpdateContext: resourceItemUpdate,
+		CustomizeDiff: resourceItemCustomizeDiff,
 		DeleteContext: resourceItemDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 		Importer: &schema.ResourceImporter{
-			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				if err := resourceItemSoftwareLicenseRead(ctx, d, meta); err.HasError() {
-					return []*schema.ResourceData{d}, errors.New(err[0].Summary)
-				}
-				return []*schema.ResourceData{d}, nil
-			},
+			StateContext: resourceItemImporter(resourceItemSoftwareLicenseRead, SoftwareLicenseCategory),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -66,36 +67,39 @@ func resourceItemSoftwareLicense() *schema.Resource {
 				},
 			},
 			"section": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				ForceNew: true,
+				Set:      sectionHash,
 				Elem:     sectionSchema(),
 			},
 			"archived": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
-				ForceNew: true,
+			},
+			"archive_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 		},
 	}
 }
 
 func resourceItemSoftwareLicenseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	m := meta.(*Meta)
-	vaultID := d.Get("vault").(string)
-	v, err := m.onePassClient.ReadItem(getID(d), vaultID)
-	if err != nil {
+	return readItem(ctx, d, meta, resourceItemSoftwareLicenseFlatten)
+}
+
+func resourceItemSoftwareLicenseFlatten(ctx context.Context, d *schema.ResourceData, meta interface{}, v *Item) diag.Diagnostics {
+	if err := checkItemCategory(v, SoftwareLicenseCategory); err != nil {
 		return diag.FromErr(err)
 	}
-	if v == nil {
-		log.Printf("[INFO] Item %s not found in %s vault", getID(d), vaultID)
-		d.SetId("")
-		return nil
-	}
-	if v.Template != Category2Template(SoftwareLicenseCategory) {
-		return diag.FromErr(errors.New("item is not from " + string(SoftwareLicenseCategory)))
-	}
 
 	d.SetId(v.UUID)
 	if err := d.Set("name", v.Overview.Title); err != nil {
@@ -132,6 +136,7 @@ func resourceItemSoftwareLicenseCreate(ctx context.Context, d *schema.ResourceDa
 	item := &Item{
 		Vault:    d.Get("vault").(string),
 		Template: Category2Template(SoftwareLicenseCategory),
+		Trashed:  ParseTrashed(d),
 		Details: Details{
 			Notes: d.Get("notes").(string),
 			Sections: append(
@@ -162,10 +167,13 @@ func resourceItemSoftwareLicenseCreate(ctx context.Context, d *schema.ResourceDa
 		},
 	}
 	m := meta.(*Meta)
-	err := m.onePassClient.CreateItem(item)
+	err := m.onePassClient.CreateItem(ctx, item)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(item.UUID)
+	if diags := archiveCreatedItem(ctx, d, meta); diags.HasError() {
+		return diags
+	}
 	return resourceItemSoftwareLicenseRead(ctx, d, meta)
 }
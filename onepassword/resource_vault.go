@@ -3,6 +3,7 @@ package onepassword
 import (
 	"context"
 	"errors"
+	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -12,7 +13,14 @@ func resourceVault() *schema.Resource {
 	return &schema.Resource{
 		ReadContext:   resourceVaultRead,
 		CreateContext: resourceVaultCreate,
+		UpdateContext: resourceVaultUpdate,
 		DeleteContext: resourceVaultDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				if err := resourceVaultRead(ctx, d, meta); err.HasError() {
@@ -27,16 +35,29 @@ func resourceVault() *schema.Resource {
 				ForceNew: true,
 				Required: true,
 			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
 
 func resourceVaultRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	m := meta.(*Meta)
-	v, err := m.onePassClient.ReadVault(getID(d))
+	v, err := m.onePassClient.ReadVault(ctx, getID(d))
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	if v == nil {
+		if d.Id() == "" {
+			return diag.Errorf("vault %s not found", getID(d))
+		}
+		log.Printf("[INFO] Vault %s not found", getID(d))
+		d.SetId("")
+		return nil
+	}
 
 	d.SetId(v.UUID)
 	if err := d.Set("name", v.Name); err != nil {
@@ -47,7 +68,7 @@ func resourceVaultRead(ctx context.Context, d *schema.ResourceData, meta interfa
 
 func resourceVaultCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	m := meta.(*Meta)
-	_, err := m.onePassClient.CreateVault(&Vault{
+	_, err := m.onePassClient.CreateVault(ctx, &Vault{
 		Name: d.Get("name").(string),
 	})
 	if err != nil {
@@ -56,9 +77,25 @@ func resourceVaultCreate(ctx context.Context, d *schema.ResourceData, meta inter
 	return resourceVaultRead(ctx, d, meta)
 }
 
+func resourceVaultUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceVaultRead(ctx, d, meta)
+}
+
 func resourceVaultDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("vault %s has deletion_protection enabled, set it to false and apply before destroying the vault", getID(d))
+	}
 	m := meta.(*Meta)
-	err := m.onePassClient.DeleteVault(getID(d))
+	if m.data.Get("refuse_non_empty_vault_delete").(bool) {
+		items, err := m.onePassClient.ListAllItems(ctx, getID(d))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(items) > 0 {
+			return diag.Errorf("vault %s still contains %d items and refuse_non_empty_vault_delete is enabled", getID(d), len(items))
+		}
+	}
+	err := m.onePassClient.DeleteVault(ctx, getID(d))
 	if err == nil {
 		d.SetId("")
 		return nil
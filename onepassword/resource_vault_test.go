@@ -0,0 +1,93 @@
+package onepassword
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_resourceVaultDelete(t *testing.T) {
+	tests := []struct {
+		name            string
+		vault           map[string]interface{}
+		provider        map[string]interface{}
+		items           string
+		wantExecResults []string
+		wantErr         bool
+	}{
+		{
+			name:            "delete",
+			vault:           map[string]interface{}{},
+			provider:        map[string]interface{}{},
+			wantExecResults: []string{"op", "delete", "vault", "uniq"},
+		},
+		{
+			name:     "deletion protection",
+			vault:    map[string]interface{}{"deletion_protection": true},
+			provider: map[string]interface{}{},
+			wantErr:  true,
+		},
+		{
+			name:            "non empty vault",
+			vault:           map[string]interface{}{},
+			provider:        map[string]interface{}{"refuse_non_empty_vault_delete": true},
+			items:           `[{ "uuid": "item" }]`,
+			wantExecResults: []string{"op", "list", "items", "--vault=uniq", "--include-trash"},
+			wantErr:         true,
+		},
+		{
+			name:            "empty vault",
+			vault:           map[string]interface{}{},
+			provider:        map[string]interface{}{"refuse_non_empty_vault_delete": true},
+			items:           `[]`,
+			wantExecResults: []string{"op", "delete", "vault", "uniq"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &mockOnePassConfig{
+				runCmd: func() (string, error) {
+					return tt.items, nil
+				},
+			}
+			m := &Meta{
+				data:          schema.TestResourceDataRaw(t, Provider().Schema, tt.provider),
+				onePassClient: mockOnePassClient(config),
+			}
+			d := schema.TestResourceDataRaw(t, resourceVault().Schema, tt.vault)
+			d.SetId("uniq")
+
+			diags := resourceVaultDelete(context.Background(), d, m)
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("resourceVaultDelete() error = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if (d.Id() == "") == tt.wantErr {
+				t.Errorf("resourceVaultDelete() id = %s, wantErr %v", d.Id(), tt.wantErr)
+			}
+			if !reflect.DeepEqual(config.execCommandResults, tt.wantExecResults) {
+				t.Errorf("resourceVaultDelete() = %v, want %v", config.execCommandResults, tt.wantExecResults)
+			}
+		})
+	}
+}
+
+func TestFakeOP_resourceVaultDelete(t *testing.T) {
+	f := newFakeOP(t)
+	vault := f.AddVault("ops")
+	f.AddItem(Item{Vault: vault.UUID, Overview: Overview{Title: "old"}, Trashed: IsTrashed})
+	m := &Meta{
+		data:          schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"refuse_non_empty_vault_delete": true}),
+		onePassClient: f.client(),
+	}
+	d := schema.TestResourceDataRaw(t, resourceVault().Schema, map[string]interface{}{})
+	d.SetId(vault.UUID)
+
+	if diags := resourceVaultDelete(context.Background(), d, m); !diags.HasError() {
+		t.Fatal("resourceVaultDelete() deleted a vault holding an archived item")
+	}
+	if d.Id() == "" {
+		t.Error("resourceVaultDelete() removed the vault from the state")
+	}
+}
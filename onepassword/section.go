@@ -1,8 +1,10 @@
 package onepassword
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
-	"strings"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -10,17 +12,26 @@ import (
 func sectionSchema() *schema.Resource {
 	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"name": {
 				Type:     schema.TypeString,
 				ForceNew: true,
 				Optional: true,
 			},
 			"field": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				ForceNew: true,
 				Optional: true,
+				Set:      fieldHash,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
 						"name": {
 							Type:     schema.TypeString,
 							ForceNew: true,
@@ -54,22 +65,10 @@ func sectionSchema() *schema.Resource {
 							ValidateDiagFunc: stringInSliceDiag([]string{"female", "male"}, true),
 						},
 						"card_type": {
-							Type:     schema.TypeString,
-							Optional: true,
-							ForceNew: true,
-							ValidateDiagFunc: stringInSliceDiag([]string{
-								"mc",
-								"visa",
-								"amex",
-								"diners",
-								"carteblanche",
-								"discover",
-								"jcb",
-								"maestro",
-								"visaelectron",
-								"laser",
-								"unionpay",
-							}, true),
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: stringInSliceDiag(cardTypes, true),
 						},
 						"email": {
 							Type:             schema.TypeString,
@@ -78,15 +77,30 @@ func sectionSchema() *schema.Resource {
 							ValidateDiagFunc: emailValidateDiag(),
 						},
 						"date": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							StateFunc:        normalizeDate,
+							ValidateDiagFunc: dateValidateDiag(),
+							Description:      "Item section field value for a date, e.g. 2006-01-02.",
+						},
+						"date_raw": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Unix timestamp 1Password stores for the date.",
 						},
 						"month_year": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							StateFunc:        normalizeMonthYear,
+							ValidateDiagFunc: monthYearValidateDiag(),
+							Description:      "Item section field value for month year, e.g. 2006-01.",
+						},
+						"month_year_raw": {
 							Type:        schema.TypeInt,
-							Optional:    true,
-							ForceNew:    true,
-							Description: "Item login section field value for month year.",
+							Computed:    true,
+							Description: "YYYYMM integer 1Password stores for the month year.",
 						},
 						"totp": {
 							Type:      schema.TypeString,
@@ -104,6 +118,7 @@ func sectionSchema() *schema.Resource {
 							Type:     schema.TypeList,
 							Optional: true,
 							ForceNew: true,
+							MaxItems: 1,
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"country": {
@@ -146,6 +161,130 @@ func sectionSchema() *schema.Resource {
 	}
 }
 
+// fieldHash hashes the field like the default set function does, with date and month year
+// values in the form they're stored in state, so 201903 and 2019-03 are the same field
+func fieldHash(v interface{}) int {
+	initHashFuncs()
+	return fieldHashFunc(normalizeField(v))
+}
+
+// sectionHash hashes the section with normalized fields, the default set function
+// would hash nested fields with their configured values
+func sectionHash(v interface{}) int {
+	m := map[string]interface{}{}
+	for k, val := range v.(map[string]interface{}) {
+		m[k] = val
+	}
+	if fields, ok := m["field"].(*schema.Set); ok {
+		normalized := make([]interface{}, 0, fields.Len())
+		for _, field := range fields.List() {
+			normalized = append(normalized, normalizeField(field))
+		}
+		m["field"] = schema.NewSet(fieldHash, normalized)
+	}
+	initHashFuncs()
+	return sectionHashFunc(m)
+}
+
+var (
+	hashFuncsOnce   sync.Once
+	fieldHashFunc   schema.SchemaSetFunc
+	sectionHashFunc schema.SchemaSetFunc
+)
+
+// initHashFuncs builds the default hash functions of sections and fields once,
+// they can't be package variables as the section schema refers to the hash functions
+func initHashFuncs() {
+	hashFuncsOnce.Do(func() {
+		section := sectionSchema()
+		fieldHashFunc = schema.HashResource(section.Schema["field"].Elem.(*schema.Resource))
+		sectionHashFunc = schema.HashResource(section)
+	})
+}
+
+func normalizeField(v interface{}) map[string]interface{} {
+	m := map[string]interface{}{}
+	for k, val := range v.(map[string]interface{}) {
+		m[k] = val
+	}
+	if s, ok := m["date"].(string); ok {
+		m["date"] = normalizeDate(s)
+	}
+	if s, ok := m["month_year"].(string); ok {
+		m["month_year"] = normalizeMonthYear(s)
+	}
+	return m
+}
+
+func urlsSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"href": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: urlValidateDiag(),
+			},
+			"label": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"primary": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+// ParseURLs returns the URL list from the urls block together with the primary URL.
+// The first URL is primary unless another one is marked explicitly.
+// Without a urls block the single url attribute is used as the primary URL.
+// 1Password only keeps the href of the primary URL, so of several URLs with the
+// same href only the first one can be marked as primary.
+func ParseURLs(d *schema.ResourceData) ([]ItemURL, string, error) {
+	uSrc := d.Get("urls").([]interface{})
+	if len(uSrc) == 0 {
+		return nil, d.Get("url").(string), nil
+	}
+	urls := make([]ItemURL, 0, len(uSrc))
+	primary := -1
+	for i, u := range uSrc {
+		ul := u.(map[string]interface{})
+		href := ul["href"].(string)
+		if ul["primary"].(bool) {
+			if primary >= 0 {
+				return nil, "", errors.New("only one of urls can be marked as primary")
+			}
+			primary = i
+		}
+		urls = append(urls, ItemURL{
+			Label: ul["label"].(string),
+			Href:  href,
+		})
+	}
+	if primary < 0 {
+		primary = 0
+	}
+	for i := 0; i < primary; i++ {
+		if urls[i].Href == urls[primary].Href {
+			return nil, "", fmt.Errorf("urls %d and %d have the same href %s, only the first of them can be marked as primary", i, primary, urls[primary].Href)
+		}
+	}
+	return urls, urls[primary].Href, nil
+}
+
+func ParseTrashed(d *schema.ResourceData) string {
+	if d.Get("archived").(bool) {
+		return IsTrashed
+	}
+	return IsNotTrashed
+}
+
 func ParseTags(d *schema.ResourceData) []string {
 	tSrc := d.Get("tags").([]interface{})
 	tags := make([]string, 0, len(tSrc))
@@ -155,31 +294,36 @@ func ParseTags(d *schema.ResourceData) []string {
 	return tags
 }
 
-func ParseField(fl map[string]interface{}) SectionField {
+func ParseField(fl map[string]interface{}, used map[string]bool) SectionField {
 	f := SectionField{
 		Text: fl["name"].(string),
 	}
 	for key, val := range fl {
-		if key == "name" {
+		if key == "name" || key == "id" {
+			continue
+		}
+		switch key {
+		case "address":
+			val = ParseAddress(val)
+		case "date":
+			val = dateValue(val)
+		case "month_year":
+			val = monthYearValue(val)
+		case "date_raw", "month_year_raw":
 			continue
 		}
 
 		isNotEmptyString := reflect.TypeOf(val).String() == "string" && val != ""
 		isNotEmptyInt := reflect.TypeOf(val).String() == "int" && val != 0
-		isNotEmptyAddress := strings.HasPrefix(reflect.TypeOf(val).String(), "map") && len(val.(map[string]interface{})) != 0
+		isNotEmptyAddress := key == "address" && len(val.(map[string]interface{})) != 0
 
 		if isNotEmptyString || isNotEmptyInt || isNotEmptyAddress {
-			f.N = f.Text
-			if val, err := fieldNumber(); err == nil {
-				f.N = val
-			}
 			f.Value = val
 			switch key {
 			case "sex":
 				f.Type = TypeSex
 			case "totp":
 				f.Type = TypeConcealed
-				f.N = "TOTP_" + f.N
 			case "month_year":
 				f.Type = TypeMonthYear
 			case "url":
@@ -189,31 +333,62 @@ func ParseField(fl map[string]interface{}) SectionField {
 			default:
 				f.Type = SectionFieldType(key)
 			}
+			if key == "totp" {
+				f.N = "TOTP_" + uniqueFieldNumber(f.Text, used)
+			} else {
+				f.N = uniqueFieldNumber(f.Text, used)
+			}
 		}
 	}
 	return f
 }
 
+// addressKeys are the parts of 1Password's address structure
+var addressKeys = []string{"city", "country", "region", "state", "street", "zip"}
+
+// ParseAddress converts the address block, a list of at most one element, to 1Password's
+// address structure. An empty map is returned when the block isn't set or all parts are empty.
+func ParseAddress(v interface{}) map[string]interface{} {
+	address := map[string]interface{}{}
+	list, _ := v.([]interface{})
+	if len(list) == 0 {
+		return address
+	}
+	src, _ := list[0].(map[string]interface{})
+	empty := true
+	for _, k := range addressKeys {
+		val, _ := src[k].(string)
+		address[k] = val
+		empty = empty && val == ""
+	}
+	if empty {
+		return map[string]interface{}{}
+	}
+	return address
+}
+
+// ParseFields derives the field identifiers from the field names,
+// duplicate names within the section get distinct identifiers.
 func ParseFields(s map[string]interface{}) []SectionField {
+	src := s["field"].(*schema.Set).List()
+	used := map[string]bool{}
 	fields := []SectionField{}
-	for _, field := range s["field"].([]interface{}) {
+	for _, field := range src {
 		fl := field.(map[string]interface{})
-		fields = append(fields, ParseField(fl))
+		fields = append(fields, ParseField(fl, used))
 	}
 	return fields
 }
 
 func ParseSections(d *schema.ResourceData) []Section {
+	src := d.Get("section").(*schema.Set).List()
+	used := map[string]bool{}
 	sections := []Section{}
-	for _, section := range d.Get("section").([]interface{}) {
+	for _, section := range src {
 		s := section.(map[string]interface{})
-		secName := s["name"].(string)
-		if val, err := fieldNumber(); err == nil {
-			secName = val
-		}
 		sections = append(sections, Section{
 			Title:  s["name"].(string),
-			Name:   "Section_" + secName,
+			Name:   "Section_" + uniqueFieldNumber(s["name"].(string), used),
 			Fields: ParseFields(s),
 		})
 	}
@@ -0,0 +1,192 @@
+package onepassword
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestParseSections(t *testing.T) {
+	first := map[string]interface{}{
+		"name": "first",
+		"field": []interface{}{
+			map[string]interface{}{"name": "user", "string": "foo"},
+			map[string]interface{}{"name": "pin", "concealed": "1234"},
+		},
+	}
+	second := map[string]interface{}{
+		"name": "second",
+		"field": []interface{}{
+			map[string]interface{}{"name": "site", "url": "https://example.com"},
+		},
+	}
+
+	got := ParseSections(schema.TestResourceDataRaw(t, resourceItemSecureNote().Schema, map[string]interface{}{
+		"section": []interface{}{first, second},
+	}))
+	reordered := ParseSections(schema.TestResourceDataRaw(t, resourceItemSecureNote().Schema, map[string]interface{}{
+		"section": []interface{}{second, first},
+	}))
+
+	if len(got) != 2 {
+		t.Fatalf("ParseSections() returned %d sections, want 2", len(got))
+	}
+	if !reflect.DeepEqual(got, reordered) {
+		t.Errorf("ParseSections() depends on configuration order:\n%v\n%v", got, reordered)
+	}
+	for _, section := range got {
+		if want := "Section_" + fieldNumber(section.Title); section.Name != want {
+			t.Errorf("ParseSections() section name = %s, want %s", section.Name, want)
+		}
+	}
+}
+
+func TestFieldNumber(t *testing.T) {
+	if fieldNumber("foo") != fieldNumber("foo") {
+		t.Error("fieldNumber() is not stable for the same name")
+	}
+	if fieldNumber("foo") == fieldNumber("bar") {
+		t.Error("fieldNumber() returned the same identifier for different names")
+	}
+	if got := fieldNumber("foo"); len(got) != 32 {
+		t.Errorf("fieldNumber() = %s, want 32 hex characters", got)
+	}
+}
+
+func TestUniqueFieldNumber(t *testing.T) {
+	used := map[string]bool{}
+	first := uniqueFieldNumber("foo", used)
+	second := uniqueFieldNumber("foo", used)
+
+	if first != fieldNumber("foo") {
+		t.Errorf("uniqueFieldNumber() = %s, want %s", first, fieldNumber("foo"))
+	}
+	if first == second {
+		t.Error("uniqueFieldNumber() returned the same identifier for a duplicate name")
+	}
+	if again := uniqueFieldNumber("foo", map[string]bool{first: true}); again != second {
+		t.Errorf("uniqueFieldNumber() = %s, want %s", again, second)
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name string
+		src  interface{}
+		want map[string]interface{}
+	}{
+		{name: "not set", src: []interface{}{}, want: map[string]interface{}{}},
+		{name: "empty", src: []interface{}{map[string]interface{}{"city": ""}}, want: map[string]interface{}{}},
+		{
+			name: "partial",
+			src:  []interface{}{map[string]interface{}{"city": "Kyiv", "zip": "46000"}},
+			want: map[string]interface{}{"city": "Kyiv", "country": "", "region": "", "state": "", "street": "", "zip": "46000"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseAddress(tt.src)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAddress() = %v, want %v", got, tt.want)
+			}
+			if len(got) == 0 {
+				return
+			}
+			// 1Password returns the address as decoded JSON
+			if back := ProcessAddress(got); !reflect.DeepEqual(back, []interface{}{got}) {
+				t.Errorf("ProcessAddress() = %v, want %v", back, []interface{}{got})
+			}
+		})
+	}
+}
+
+func TestParseURLs(t *testing.T) {
+	a := map[string]interface{}{"href": "https://a.example.com", "label": "a"}
+	b := map[string]interface{}{"href": "https://b.example.com", "label": "b"}
+	primary := func(u map[string]interface{}, label string) map[string]interface{} {
+		return map[string]interface{}{"href": u["href"], "label": label, "primary": true}
+	}
+
+	tests := []struct {
+		name        string
+		raw         map[string]interface{}
+		wantPrimary int
+		wantHref    string
+		wantErr     bool
+	}{
+		{
+			name:        "legacy url",
+			raw:         map[string]interface{}{"url": "https://a.example.com"},
+			wantPrimary: -1,
+			wantHref:    "https://a.example.com",
+		},
+		{
+			name:     "first url is primary",
+			raw:      map[string]interface{}{"urls": []interface{}{a, b}},
+			wantHref: "https://a.example.com",
+		},
+		{
+			name:        "marked primary",
+			raw:         map[string]interface{}{"urls": []interface{}{a, primary(b, "b")}},
+			wantPrimary: 1,
+			wantHref:    "https://b.example.com",
+		},
+		{
+			name:    "two primaries",
+			raw:     map[string]interface{}{"urls": []interface{}{primary(a, "a"), primary(b, "b")}},
+			wantErr: true,
+		},
+		{
+			name:        "duplicate href, first one marked",
+			raw:         map[string]interface{}{"urls": []interface{}{b, primary(a, "a"), map[string]interface{}{"href": a["href"], "label": "copy"}}},
+			wantPrimary: 1,
+			wantHref:    "https://a.example.com",
+		},
+		{
+			name:    "duplicate href, second one marked",
+			raw:     map[string]interface{}{"urls": []interface{}{a, primary(a, "copy")}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls, href, err := ParseURLs(schema.TestResourceDataRaw(t, resourceItemLogin().Schema, tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseURLs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if href != tt.wantHref {
+				t.Errorf("ParseURLs() primary = %s, want %s", href, tt.wantHref)
+			}
+
+			// reading the item back marks exactly the configured URL as primary
+			for i, u := range ProcessURLs(Overview{URL: href, URLs: urls}) {
+				if got := u["primary"].(bool); got != (i == tt.wantPrimary) {
+					t.Errorf("ProcessURLs() urls[%d] primary = %v, want %v", i, got, i == tt.wantPrimary)
+				}
+			}
+		})
+	}
+}
+
+func TestParseURLs_conflictsWithURL(t *testing.T) {
+	diags := resourceItemLogin().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "example",
+		"url":  "https://a.example.com",
+		"urls": []interface{}{map[string]interface{}{"href": "https://b.example.com"}},
+	}))
+	if !diags.HasError() {
+		t.Fatal("Validate() accepted both url and urls")
+	}
+	for _, d := range diags {
+		if strings.Contains(d.Detail, "conflicts with") {
+			return
+		}
+	}
+	t.Errorf("Validate() didn't report the conflict: %v", diags)
+}
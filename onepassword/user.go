@@ -1,7 +1,9 @@
 package onepassword
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 )
 
 const (
@@ -26,12 +28,17 @@ type User struct {
 
 // ReadUser gets an existing 1Password User
 // This supports multiple id parameter values, including "First Last", "Email", and "UUID".
-func (o *OnePassClient) ReadUser(id string) (*User, error) {
+// nil is returned when the User doesn't exist.
+func (o *OnePassClient) ReadUser(ctx context.Context, id string) (*User, error) {
 	user := &User{}
 	args := []string{opPasswordGet, UserResource, id}
-	res, err := o.RunSimpleCmd(args...)
+	res, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
-		return nil, prettyError(args, res, err)
+		err = prettyError(args, res, err)
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
 	}
 	if err = json.Unmarshal(res, user); err != nil {
 		return nil, err
@@ -1,7 +1,9 @@
 package onepassword
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 )
 
 const VaultResource = "vault"
@@ -11,12 +13,16 @@ type Vault struct {
 	Name string
 }
 
-func (o *OnePassClient) ReadVault(id string) (*Vault, error) {
+func (o *OnePassClient) ReadVault(ctx context.Context, id string) (*Vault, error) {
 	vault := &Vault{}
 	args := []string{opPasswordGet, VaultResource, id}
-	res, err := o.RunSimpleCmd(args...)
+	res, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
-		return nil, prettyError(args, res, err)
+		err = prettyError(args, res, err)
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
 	}
 	if err = json.Unmarshal(res, vault); err != nil {
 		return nil, err
@@ -24,9 +30,9 @@ func (o *OnePassClient) ReadVault(id string) (*Vault, error) {
 	return vault, nil
 }
 
-func (o *OnePassClient) CreateVault(v *Vault) (*Vault, error) {
+func (o *OnePassClient) CreateVault(ctx context.Context, v *Vault) (*Vault, error) {
 	args := []string{opPasswordCreate, VaultResource, v.Name}
-	res, err := o.RunSimpleCmd(args...)
+	res, err := o.RunSimpleCmd(ctx, args...)
 	if err != nil {
 		return nil, prettyError(args, res, err)
 	}
@@ -36,6 +42,6 @@ func (o *OnePassClient) CreateVault(v *Vault) (*Vault, error) {
 	return v, nil
 }
 
-func (o *OnePassClient) DeleteVault(id string) error {
-	return o.Delete(VaultResource, id)
+func (o *OnePassClient) DeleteVault(ctx context.Context, id string) error {
+	return o.Delete(ctx, VaultResource, id)
 }